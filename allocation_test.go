@@ -0,0 +1,64 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Allocation", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		partTime := NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil)
+		// Dedicado al 50% hasta el 14 de junio y después al 100%
+		partTime.Allocations = []*Allocation{
+			NewAllocation(parseDate("2021-06-14"), 100),
+			NewAllocation(parseDate("2021-06-01"), 50),
+		}
+
+		plan = NewProjectPlan("test-plan",
+			[]*Task{NewTask("Tarea1", "Summary", "backend", 10, 4)},
+			[]*Resource{partTime},
+			nil)
+	})
+
+	When("planificamos con un recurso a tiempo parcial", func() {
+
+		It("La tarea debe durar más en días de calendario", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-15 ahg",
+			})
+			Expect(plan.TotalDuration).Should(BeEquivalentTo(4))
+			Expect(plan.Workdays).Should(BeEquivalentTo(7))
+		})
+
+		It("La revisión debe tener en cuenta la dedicación", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			err = gplan.Review(plan, parseDate("2021-06-10"))
+			Expect(err).Should(BeNil())
+			Expect(plan.Tasks[0].ExpectedCompleteDuration).Should(BeEquivalentTo(1))
+			Expect(plan.Tasks[0].ExpectedProgress).Should(BeEquivalentTo(37))
+		})
+	})
+
+	When("el porcentaje de dedicación no está entre 1 y 100", func() {
+
+		It("Debe devolver un error", func() {
+			plan.Resources[0].Allocations[0].Percentage = 0
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("el recurso ahg tiene un porcentaje de dedicación que no está entre 1 y 100")))
+		})
+	})
+
+})
@@ -0,0 +1,79 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Availability", func() {
+
+	var (
+		tasks     []*Task
+		resources []*Resource
+	)
+
+	BeforeEach(func() {
+		resources = []*Resource{
+			NewResource("externo", "Contratista", "backend", parseDate("2021-06-07"), nil),
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-09"), nil),
+		}
+
+		// El contrato termina el viernes y ahg está cedido a otro proyecto la semana siguiente
+		resources[0].AvailableUntil = parseDate("2021-06-11")
+		resources[1].Unavailabilities = []*Unavailability{
+			NewUnavailability(parseDate("2021-06-14"), parseDate("2021-06-18"), "Cesión a otro proyecto"),
+		}
+
+		tasks = []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 3),
+			NewTask("Tarea2", "Summary", "backend", 20, 3),
+			NewTask("Tarea3", "Summary", "backend", 30, 3),
+		}
+	})
+
+	When("los recursos dejan de estar disponibles", func() {
+
+		It("Las tareas deben asignarse a recursos que puedan terminarlas", func() {
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			// Tarea2 y Tarea3 no se pueden terminar antes de que se vaya el contratista
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-09 externo",
+				"2021-06-09 2021-06-11 ahg",
+				"2021-06-21 2021-06-23 ahg",
+			})
+		})
+	})
+
+	When("ningún recurso puede terminar una tarea antes de dejar de estar disponible", func() {
+
+		It("Debe devolver un error", func() {
+			tasks[0].RequiredResourceID = &resources[0].ID
+			tasks[0].Duration = 6
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas no se pueden terminar antes de que sus recursos dejen de estar disponibles")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+		})
+	})
+
+	When("un recurso deja de estar disponible antes de estarlo", func() {
+
+		It("Debe devolver un error", func() {
+			resources[1].AvailableUntil = parseDate("2021-06-08")
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("el recurso ahg deja de estar disponible antes de estar disponible")))
+		})
+	})
+
+})
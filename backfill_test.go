@@ -0,0 +1,87 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backfill", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("David.Attrache", "David Attrache", "maquetacion", parseDate("2021-06-07"), nil),
+		}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "maquetacion", 10, 5),
+			NewTask("Tarea2", "Summary", "backend", 20, 2),
+			NewTask("Tarea3", "Summary", "backend", 30, 3),
+			NewTask("Tarea4", "Summary", "backend", 40, 4),
+		}
+
+		// Tarea2 deja a ahg sin trabajo la primera semana
+		BlocksTo(tasks[0], tasks[1])
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("planificamos a continuación de la última tarea de cada recurso", func() {
+
+		It("Los huecos de los recursos no se deben aprovechar", func() {
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-07 2021-06-11 David.Attrache",
+				"Tarea2 2021-06-14 2021-06-15 ahg",
+				"Tarea3 2021-06-16 2021-06-18 ahg",
+				"Tarea4 2021-06-21 2021-06-24 ahg",
+			}))
+		})
+	})
+
+	When("planificamos rellenando los huecos de los recursos", func() {
+
+		It("Las tareas deben ocupar el primer hueco en el que quepan", func() {
+			plan.SchedulingMode = gplan.BackfillScheduling
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			// Tarea3 cabe antes de Tarea2 pero Tarea4 no cabe en los dos días que quedan libres
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-07 2021-06-11 David.Attrache",
+				"Tarea2 2021-06-14 2021-06-15 ahg",
+				"Tarea3 2021-06-07 2021-06-09 ahg",
+				"Tarea4 2021-06-16 2021-06-21 ahg",
+			}))
+			Expect(schedule.GetEndDate()).Should(Equal(parseDate("2021-06-21")))
+
+			var timeline []string
+			for _, booking := range schedule.GetResourceTimeline("ahg") {
+				timeline = append(timeline, fmt.Sprintf("%s %s %s", booking.TaskID,
+					booking.StartDate.Format("2006-01-02"), booking.EndDate.Format("2006-01-02")))
+			}
+			Expect(timeline).Should(Equal([]string{
+				"Tarea3 2021-06-07 2021-06-09",
+				"Tarea2 2021-06-14 2021-06-15",
+				"Tarea4 2021-06-16 2021-06-21",
+			}))
+		})
+
+		It("Al aplicarla la siguiente fecha de disponibilidad debe ser la del fin de la última tarea", func() {
+			plan.SchedulingMode = gplan.BackfillScheduling
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+			Expect(plan.Resources[0].GetNextAvailableDate()).Should(Equal(parseDate("2021-06-22")))
+		})
+	})
+
+})
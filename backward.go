@@ -0,0 +1,397 @@
+package gplan
+
+import (
+	"sort"
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// PlanningBackward igual que Planning pero planificando hacia atrás desde una fecha límite con PlanScheduleBackward
+func PlanningBackward(deadline time.Time, plan ProjectPlan) *Error {
+
+	// Ordena las tareas del plan por número de orden como se ha hecho siempre
+	plan.SortTasksByOrder()
+
+	schedule, err := PlanScheduleBackward(deadline, plan)
+	if err != nil {
+		return err
+	}
+
+	schedule.Apply(plan)
+
+	return nil
+}
+
+// PlanScheduleBackward calcula la planificación de un plan hacia atrás a partir de una fecha límite, el último día en
+// el que se puede trabajar en el proyecto. Cada tarea se planifica lo más tarde posible respetando sus dependencias, la
+// agenda de los recursos y los días no laborables, de manera que la fecha de comienzo de cada tarea y la del plan son
+// las fechas más tardías en las que pueden comenzar para terminar a tiempo.
+// Cada tarea se asigna al recurso con el que puede comenzar más tarde, siempre a continuación hacia atrás de las
+// tareas que ya tiene asignadas. No tiene en cuenta el modo de planificación ni la estrategia de asignación del plan, y
+// no admite tareas divisibles ni tareas que reparten su duración entre varios recursos
+func PlanScheduleBackward(deadline time.Time, plan ProjectPlan) (*Schedule, *Error) {
+
+	// Convierte a local deadline
+	deadline = deadline.Local()
+
+	var (
+		tasks     = plan.GetTasks()
+		resources = plan.GetResources()
+	)
+
+	// Ordena por número de orden una copia de la lista de tareas para no modificar el plan
+	tasks = append([]Task{}, tasks...)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].GetOrder() < tasks[j].GetOrder()
+	})
+
+	tasksIndex, warnings, err := validateTasks(tasks, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsupported []TaskID
+	for _, task := range tasks {
+		if task.IsSplittable() || task.GetMaxTeamSize() > 1 {
+			unsupported = append(unsupported, task.GetID())
+		}
+	}
+	if len(unsupported) > 0 {
+		return nil, newError(ErrUnschedulable, "las siguientes tareas no se pueden planificar hacia atrás", unsupported)
+	}
+
+	// Las tareas se planifican en orden topológico inverso, de manera que una tarea siempre se planifica después de
+	// las tareas a las que bloquea
+	tasks, err = sortTasksByDependencies(tasks, tasksIndex, ManualOrderRule)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &backwardPlanner{
+		planner:              newPlanner(time.Time{}, plan, tasksIndex),
+		deadline:             deadline,
+		latestAvailableDates: make(map[ResourceID]time.Time),
+	}
+	p.warnings = append(p.warnings, warnings...)
+
+	for i := len(tasks) - 1; i >= 0; i-- {
+		err = p.assignTask(tasks[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(p.unmetConstraints) > 0 {
+		return nil, newError(ErrUnmetConstraint, "no se pueden cumplir las restricciones de fecha de las siguientes tareas", p.unmetConstraints)
+	}
+
+	// Las tareas se han planificado de la última a la primera
+	for i, j := 0, len(p.scheduled)-1; i < j; i, j = i+1, j-1 {
+		p.scheduled[i], p.scheduled[j] = p.scheduled[j], p.scheduled[i]
+	}
+
+	return p.schedule(), nil
+}
+
+// backwardPlanner contiene el estado de una planificación hacia atrás
+type backwardPlanner struct {
+	*planner
+	// Último día en el que se puede trabajar en el proyecto
+	deadline time.Time
+	// Último día en el que está libre cada recurso, el anterior a la primera tarea que tiene asignada
+	latestAvailableDates map[ResourceID]time.Time
+}
+
+// assignTask planifica una tarea lo más tarde posible y la asigna a un recurso
+func (p *backwardPlanner) assignTask(task Task) *Error {
+
+	maxStartDate, maxEndDate, err := p.getLatestDates(task)
+	if err != nil {
+		return err
+	}
+
+	if IsMilestone(task) {
+		p.assignMilestone(task, maxStartDate, maxEndDate)
+		return nil
+	}
+
+	scheduledTaskInfo, err := p.latestScheduledTask(task, maxStartDate, maxEndDate)
+	if err != nil {
+		return err
+	}
+
+	p.checkConstraint(task, scheduledTaskInfo.StartDate, scheduledTaskInfo.EndDate)
+	p.book(task, scheduledTaskInfo)
+
+	// Cada recurso queda libre hasta el día anterior al comienzo de la tarea
+	for i, resource := range scheduledTaskInfo.Team {
+		p.latestAvailableDates[resource.GetID()] = scheduledTaskInfo.StartDates[i].AddDate(0, 0, -1)
+	}
+
+	return nil
+}
+
+// getLatestDates devuelve la fecha más tardía en la que puede comenzar una tarea, vacía si no hay ninguna, y la fecha
+// más tardía en la que puede terminar según las tareas a las que bloquea, su restricción de fecha y la fecha límite.
+// Es la inversa de getRealStartDate. Un hito cuenta como terminado el día laborable anterior a su fecha
+func (p *backwardPlanner) getLatestDates(task Task) (time.Time, time.Time, *Error) {
+
+	var (
+		maxStartDate time.Time
+		maxEndDate   = p.deadline
+	)
+
+	setMaxStartDate := func(date time.Time) {
+		if maxStartDate.IsZero() || dateutil.IsLt(date, maxStartDate) {
+			maxStartDate = date
+		}
+	}
+	setMaxEndDate := func(date time.Time) {
+		if dateutil.IsLt(date, maxEndDate) {
+			maxEndDate = date
+		}
+	}
+
+	for _, dep := range p.blocksTo[task.GetID()] {
+
+		assignment := p.assignments[dep.GetTaskID()]
+
+		if assignment == nil {
+			return time.Time{}, time.Time{}, newTextError(ErrUnplannedTask, "La tarea %s no está planificada y es bloqueada por la tarea %s que está en planificación",
+				dep.GetTaskID(), task.GetID())
+		}
+
+		// Fecha en la que termina la tarea bloqueada, un hito termina el día laborable anterior a su fecha
+		endDate := assignment.EndDate
+		if assignment.Milestone {
+			endDate = p.calendar.laborableDate(endDate, -1)
+		}
+
+		switch dep.GetType() {
+		case StartToStart:
+			setMaxStartDate(p.calendar.laborableDate(assignment.StartDate, -dep.GetLag()))
+		case FinishToFinish:
+			setMaxEndDate(p.calendar.laborableDate(endDate, -dep.GetLag()))
+		case StartToFinish:
+			setMaxStartDate(p.calendar.laborableDate(endDate, 1-dep.GetLag()))
+		default:
+			// Debe terminar como tarde el día anterior al comienzo de la tarea que bloquea
+			if dep.GetLag() != 0 || IsMilestone(task) {
+				setMaxEndDate(p.calendar.laborableDate(assignment.StartDate, -dep.GetLag()-1))
+			} else {
+				setMaxEndDate(assignment.StartDate.AddDate(0, 0, -1))
+			}
+		}
+	}
+
+	// Las restricciones FinishNoLaterThan, StartNoLaterThan y MustStartOn impiden que termine o comience después de
+	// su fecha
+	switch task.GetConstraintType() {
+	case FinishNoLaterThan:
+		setMaxEndDate(task.GetConstraintDate().Local())
+	case StartNoLaterThan, MustStartOn:
+		setMaxStartDate(task.GetConstraintDate().Local())
+	}
+
+	return maxStartDate, maxEndDate, nil
+}
+
+// assignMilestone planifica un hito lo más tarde posible, el día laborable siguiente a la fecha en la que como tarde
+// tiene que estar terminado
+func (p *backwardPlanner) assignMilestone(task Task, maxStartDate time.Time, maxEndDate time.Time) {
+
+	var date = p.calendar.laborableDate(maxEndDate, 1)
+
+	if !maxStartDate.IsZero() && dateutil.IsLt(maxStartDate, date) {
+		date = maxStartDate
+	}
+
+	if !p.calendar.isLaborableDay(date) {
+		date = p.calendar.laborableDate(date, -1)
+	}
+
+	p.checkConstraint(task, date, date)
+
+	p.assignments[task.GetID()] = &Assignment{
+		TaskID:    task.GetID(),
+		StartDate: date,
+		EndDate:   date,
+		Milestone: true,
+	}
+	p.scheduled = append(p.scheduled, task.GetID())
+}
+
+// latestScheduledTask calcula la planificación más tardía de una tarea eligiendo los recursos que necesita
+func (p *backwardPlanner) latestScheduledTask(task Task, maxStartDate time.Time, maxEndDate time.Time) (*scheduledTaskInfo, *Error) {
+
+	var (
+		team          []teamMember
+		chosen        = make(map[ResourceID]bool)
+		bestScheduled *scheduledTaskInfo
+		err           *Error
+	)
+
+	for _, demand := range taskDemands(task) {
+		for n := uint(0); n < demand.GetQuantity(); n++ {
+			bestScheduled, err = p.chooseLatestResource(task, demand.GetResourceType(), chosen, maxStartDate, maxEndDate)
+			if err != nil {
+				return nil, err
+			}
+			chosen[bestScheduled.Resource.GetID()] = true
+			team = append(team, teamMember{resource: bestScheduled.Resource, taskType: demand.GetResourceType()})
+		}
+	}
+
+	// Si son varios recursos la tarea se planifica cuando estén libres todos a la vez
+	if len(team) > 1 {
+		var calendars []*workCalendar
+		for _, member := range team {
+			calendars = append(calendars, p.resourceProfiles[member.resource.GetID()].calendar)
+		}
+		if !shareWorkingWeekday(calendars) {
+			return nil, newError(ErrUnschedulable, "los recursos elegidos para las siguientes tareas no tienen ningún día laborable en común",
+				[]TaskID{task.GetID()})
+		}
+		bestScheduled = p.latestTeamTask(task, team, maxStartDate, maxEndDate)
+		if bestScheduled.Incomplete {
+			return nil, newError(ErrUnschedulable, "las siguientes tareas no se pueden terminar antes de la fecha límite", []TaskID{task.GetID()})
+		}
+	}
+
+	return bestScheduled, nil
+}
+
+// chooseLatestResource calcula la planificación más tardía de la tarea para cada recurso de eligibleResources y
+// retorna la del recurso con el que puede comenzar más tarde
+func (p *backwardPlanner) chooseLatestResource(task Task, taskType string, chosen map[ResourceID]bool,
+	maxStartDate time.Time, maxEndDate time.Time) (*scheduledTaskInfo, *Error) {
+
+	var (
+		resources      = p.eligibleResources(task, taskType, chosen)
+		scheduledTasks []*scheduledTaskInfo
+	)
+
+	if len(resources) == 0 {
+		return nil, newError(ErrNotEnoughResources, "no hay suficientes recursos para las siguientes tareas", []TaskID{task.GetID()})
+	}
+
+	for _, resource := range resources {
+		scheduledTasks = append(scheduledTasks,
+			p.latestTeamTask(task, []teamMember{{resource: resource, taskType: taskType}}, maxStartDate, maxEndDate))
+	}
+
+	// Solo se tienen en cuenta los recursos que están disponibles a tiempo para terminar la tarea y entre ellos elige
+	// con el que puede comenzar más tarde
+	return p.pickCandidate(task, scheduledTasks,
+		"las siguientes tareas no se pueden terminar antes de la fecha límite",
+		func(scheduledTasks []*scheduledTaskInfo) (*scheduledTaskInfo, *Error) {
+			var best = scheduledTasks[0]
+			for _, sh := range scheduledTasks[1:] {
+				if dateutil.IsGt(sh.StartDate, best.StartDate) ||
+					(dateutil.IsEqual(sh.StartDate, best.StartDate) && dateutil.IsGt(sh.EndDate, best.EndDate)) {
+					best = sh
+				}
+			}
+			return best, nil
+		})
+}
+
+// latestTeamTask planifica una tarea lo más tarde posible con varios recursos trabajando a la vez, terminando como
+// tarde en maxEndDate y el día anterior a la primera tarea que ya tiene asignada cada recurso
+func (p *backwardPlanner) latestTeamTask(task Task, team []teamMember, maxStartDate time.Time, maxEndDate time.Time) *scheduledTaskInfo {
+
+	var (
+		endDate   = maxEndDate
+		resources []Resource
+	)
+
+	for _, member := range team {
+		resources = append(resources, member.resource)
+
+		if latest, exist := p.latestAvailableDates[member.resource.GetID()]; exist && dateutil.IsLt(latest, endDate) {
+			endDate = latest
+		}
+	}
+
+	startDate, endDate, complete := p.fitTaskBackward(task, team, endDate, maxStartDate)
+
+	var (
+		startDates = make([]time.Time, 0, len(team))
+		endDates   = make([]time.Time, 0, len(team))
+		efforts    = make([]uint, 0, len(team))
+	)
+	for range team {
+		startDates = append(startDates, startDate)
+		endDates = append(endDates, endDate)
+		efforts = append(efforts, task.GetDuration()*100)
+	}
+
+	return &scheduledTaskInfo{
+		Resource:   resources[0],
+		Team:       resources,
+		StartDates: startDates,
+		EndDates:   endDates,
+		Efforts:    efforts,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Incomplete: !complete,
+	}
+}
+
+// fitTaskBackward calcula las fechas de una tarea contando su duración hacia atrás desde una fecha de fin con la
+// capacidad de trabajo de varios recursos que trabajan a la vez. Si maxStartDate no está vacía adelanta el fin de la
+// tarea hasta que su comienzo sea igual o anterior a ella. Devuelve False si algún recurso no está disponible todavía
+// en la fecha en la que tendría que comenzar
+func (p *backwardPlanner) fitTaskBackward(task Task, team []teamMember, endDate time.Time, maxStartDate time.Time) (time.Time, time.Time, bool) {
+
+	var (
+		profiles      []*resourceProfile
+		taskTypes     []string
+		availableFrom time.Time
+		realEndDate   = endDate
+		startDate     time.Time
+	)
+
+	for _, member := range team {
+		profiles = append(profiles, p.resourceProfiles[member.resource.GetID()])
+		taskTypes = append(taskTypes, member.taskType)
+		if from := p.availableFrom[member.resource.GetID()]; dateutil.IsGt(from, availableFrom) {
+			availableFrom = from
+		}
+	}
+
+	for {
+
+		// El trabajo pendiente se cuenta en porcentaje de jornadas, una jornada completa es 100
+		pendingWork := task.GetDuration() * 100
+		startDate = realEndDate
+
+		for {
+
+			if !availableFrom.IsZero() && dateutil.IsLt(startDate, availableFrom) {
+				return startDate, realEndDate, false
+			}
+
+			if capacity := teamCapacity(profiles, taskTypes, startDate); capacity > 0 {
+				// Si es el primer día que cuenta actualiza la fecha de fin
+				if pendingWork == task.GetDuration()*100 {
+					realEndDate = startDate
+				}
+				if capacity >= pendingWork {
+					break
+				}
+				pendingWork -= capacity
+			}
+			startDate = startDate.AddDate(0, 0, -1)
+		}
+
+		// Si comienza después de lo que le permiten las tareas a las que bloquea se adelanta el fin un día
+		if maxStartDate.IsZero() || dateutil.IsLte(startDate, maxStartDate) {
+			break
+		}
+		realEndDate = realEndDate.AddDate(0, 0, -1)
+	}
+
+	return startDate, realEndDate, true
+}
@@ -0,0 +1,91 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backward", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		tasks := []*Task{
+			NewTask("Desarrollo", "Summary", "backend", 10, 5),
+			NewTask("Documentación", "Summary", "backend", 15, 2),
+			NewTask("QA", "Summary", "qa", 20, 2),
+			NewTask("Lanzamiento", "Lanzamiento 1.0", "", 30, 0),
+		}
+
+		BlocksTo(tasks[0], tasks[2])
+		BlocksTo(tasks[2], tasks[3])
+
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		feastDays := []*Holidays{
+			NewHolidays(parseDate("2021-06-24"), parseDate("2021-06-24")),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, feastDays)
+	})
+
+	When("planificamos hacia atrás desde una fecha límite", func() {
+
+		It("Las tareas deben planificarse lo más tarde posible", func() {
+			err := gplan.PlanningBackward(parseDate("2021-06-25"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-16 2021-06-22 ahg",
+				"2021-06-23 2021-06-25 ahg",
+				"2021-06-23 2021-06-25 Noemi",
+				"2021-06-28 2021-06-28 -",
+			})
+			Expect(plan.StartDate.Format("2006-01-02")).Should(Equal("2021-06-16"))
+			Expect(plan.EndDate.Format("2006-01-02")).Should(Equal("2021-06-25"))
+		})
+
+		It("La planificación debe tener la fecha más tardía de comienzo de cada tarea", func() {
+			schedule, err := gplan.PlanScheduleBackward(parseDate("2021-06-25"), plan)
+			Expect(err).Should(BeNil())
+
+			desarrollo, _ := schedule.GetAssignment("Desarrollo")
+			Expect(desarrollo.StartDate.Format("2006-01-02")).Should(Equal("2021-06-16"))
+			Expect(schedule.GetStartDate().Format("2006-01-02")).Should(Equal("2021-06-16"))
+
+			// No debe modificar el plan
+			Expect(plan.Tasks[0].StartDate.IsZero()).Should(BeTrue())
+		})
+	})
+
+	When("los recursos no están disponibles a tiempo para terminar antes de la fecha límite", func() {
+
+		It("Debe devolver un error", func() {
+			plan.Resources[0].AvailableFrom = parseDate("2021-06-21")
+
+			err := gplan.PlanningBackward(parseDate("2021-06-25"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas no se pueden terminar antes de la fecha límite")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Desarrollo"}))
+		})
+	})
+
+	When("alguna tarea es divisible", func() {
+
+		It("Debe devolver un error", func() {
+			plan.Tasks[1].Splittable = true
+
+			err := gplan.PlanningBackward(parseDate("2021-06-25"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas no se pueden planificar hacia atrás")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Documentación"}))
+		})
+	})
+
+})
@@ -0,0 +1,86 @@
+package gplan_test
+
+import (
+	"time"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Calendar", func() {
+
+	var (
+		sundayToThursday = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday}
+		fourDayWeek      = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday}
+	)
+
+	When("calculamos días laborables con un calendario", func() {
+
+		It("Debe usar los días laborables de la semana del calendario", func() {
+			calendar := NewCalendar(sundayToThursday, nil)
+
+			Expect(gplan.IsLaborableDayInCalendar(parseDate("2021-06-06"), calendar, nil)).Should(BeTrue())
+			Expect(gplan.IsLaborableDayInCalendar(parseDate("2021-06-11"), calendar, nil)).Should(BeFalse())
+			Expect(gplan.CalculateLaborableDaysInCalendar(parseDate("2021-06-06"), parseDate("2021-06-13"), calendar, nil)).
+				Should(BeEquivalentTo(6))
+			Expect(gplan.CalculateLaborableDateInCalendar(parseDate("2021-06-10"), 1, calendar, nil)).
+				Should(Equal(parseDate("2021-06-13")))
+		})
+
+		It("Sin calendario debe trabajar de lunes a viernes", func() {
+			Expect(gplan.CalculateLaborableDaysInCalendar(parseDate("2021-06-06"), parseDate("2021-06-13"), nil, nil)).
+				Should(BeEquivalentTo(5))
+		})
+	})
+
+	Describe("Planificación y revisión con calendarios", func() {
+
+		var plan *ProjectPlan
+
+		BeforeEach(func() {
+			tasks := []*Task{
+				NewTask("Tarea1", "Summary", "backend", 10, 5),
+				NewTask("Tarea2", "Summary", "qa", 20, 6),
+			}
+
+			contractor := NewResource("contractor", "Contractor", "qa", parseDate("2021-06-06"), nil)
+			// El recurso trabaja cuatro días a la semana y no le afectan los días no laborables del calendario del plan
+			contractor.Calendar = NewCalendar(fourDayWeek, nil)
+
+			resources := []*Resource{
+				NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-06"), nil),
+				contractor,
+			}
+
+			plan = NewProjectPlan("test-plan", tasks, resources, nil)
+			plan.Calendar = NewCalendar(sundayToThursday, []*Holidays{
+				NewHolidays(parseDate("2021-06-08"), parseDate("2021-06-08")),
+			})
+
+			err := gplan.Planning(parseDate("2021-06-06"), plan)
+			Expect(err).Should(BeNil())
+		})
+
+		It("El plan debe ser igual al siguiente plan", func() {
+			comparePlan(plan.Tasks, []string{
+				"2021-06-06 2021-06-13 ahg",
+				"2021-06-07 2021-06-15 contractor",
+			})
+			Expect(plan.StartDate.Format("2006-01-02")).Should(Equal("2021-06-06"))
+			Expect(plan.EndDate.Format("2006-01-02")).Should(Equal("2021-06-15"))
+			Expect(plan.Workdays).Should(BeEquivalentTo(7))
+		})
+
+		It("La revisión debe usar el calendario de cada recurso", func() {
+			err := gplan.Review(plan, parseDate("2021-06-09"))
+			Expect(err).Should(BeNil())
+			Expect(plan.Tasks[0].ExpectedCompleteDuration).Should(BeEquivalentTo(2))
+			Expect(plan.Tasks[0].ExpectedProgress).Should(BeEquivalentTo(40))
+			Expect(plan.Tasks[1].ExpectedCompleteDuration).Should(BeEquivalentTo(2))
+			Expect(plan.Tasks[1].ExpectedProgress).Should(BeEquivalentTo(33))
+			Expect(plan.ExpectedProgress).Should(BeEquivalentTo(36))
+		})
+	})
+
+})
@@ -2,6 +2,7 @@ package gplan
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/antoniohueso/gplan/dateutil"
@@ -9,6 +10,255 @@ import (
 
 // CalculateLaborableDate devuelve una fecha laborable a partir de una fecha sumando o restando los días que recibe como parámetro.
 func CalculateLaborableDate(from time.Time, days int, holidays []Holidays) time.Time {
+	return newWorkCalendar(nil, holidays).laborableDate(from, days)
+}
+
+// CalculateLaborableDays Devuelve los días laborables que hay entre dos fechas, incluidas ambas.
+func CalculateLaborableDays(from time.Time, to time.Time, holidays []Holidays) uint {
+	return newWorkCalendar(nil, holidays).laborableDays(from, to)
+}
+
+// IsLaborableDay devuelve True si el día que recibe como parámetro es laborable
+func IsLaborableDay(day time.Time, feastDays []Holidays) bool {
+	return newWorkCalendar(nil, feastDays).isLaborableDay(day)
+}
+
+// CalculateLaborableDateInCalendar igual que CalculateLaborableDate pero usando los días laborables de la semana y los
+// días no laborables de un calendario. Si el calendario es nil se trabaja de lunes a viernes
+func CalculateLaborableDateInCalendar(from time.Time, days int, calendar Calendar, holidays []Holidays) time.Time {
+	return newWorkCalendar(calendar, holidays).laborableDate(from, days)
+}
+
+// CalculateLaborableDaysInCalendar igual que CalculateLaborableDays pero usando los días laborables de la semana y los
+// días no laborables de un calendario. Si el calendario es nil se trabaja de lunes a viernes
+func CalculateLaborableDaysInCalendar(from time.Time, to time.Time, calendar Calendar, holidays []Holidays) uint {
+	return newWorkCalendar(calendar, holidays).laborableDays(from, to)
+}
+
+// IsLaborableDayInCalendar igual que IsLaborableDay pero usando los días laborables de la semana y los días no
+// laborables de un calendario. Si el calendario es nil se trabaja de lunes a viernes
+func IsLaborableDayInCalendar(day time.Time, calendar Calendar, holidays []Holidays) bool {
+	return newWorkCalendar(calendar, holidays).isLaborableDay(day)
+}
+
+// isPlanned devuelve True si la tarea está planificada: tiene fecha de comienzo y, salvo que sea un hito, que no
+// tiene recurso, un recurso asignado
+func isPlanned(task Task) bool {
+	return !task.GetStartDate().IsZero() && (IsMilestone(task) || task.GetResourceID() != nil)
+}
+
+// IsMilestone devuelve True si la tarea es un hito, es decir, una tarea sin duración que no necesita recurso
+func IsMilestone(task Task) bool {
+	return task.GetDuration() == 0
+}
+
+// resourceDemand demanda de recursos por defecto de una tarea, un recurso de su tipo
+type resourceDemand struct {
+	resourceType string
+	quantity     uint
+}
+
+func (d resourceDemand) GetResourceType() string {
+	return d.resourceType
+}
+
+func (d resourceDemand) GetQuantity() uint {
+	return d.quantity
+}
+
+// taskDemands devuelve los recursos que necesita una tarea. Si no tiene ninguna demanda necesita un recurso de su tipo
+func taskDemands(task Task) []ResourceDemand {
+	if demands := task.GetResourceDemands(); len(demands) > 0 {
+		return demands
+	}
+	return []ResourceDemand{resourceDemand{resourceType: task.GetResourceType(), quantity: 1}}
+}
+
+// taskResourceIDs devuelve los recursos asignados a una tarea, todos si tiene varios o el único que tiene
+func taskResourceIDs(task Task) []ResourceID {
+	if resourceIDs := task.GetResourceIDs(); len(resourceIDs) > 0 {
+		return resourceIDs
+	}
+	if task.GetResourceID() != nil {
+		return []ResourceID{*task.GetResourceID()}
+	}
+	return nil
+}
+
+// plannedEfforts devuelve el trabajo planificado de cada recurso de una tarea en el orden de sus recursos, o nil si la
+// tarea no lo tiene o no coincide con sus recursos y fechas, por ejemplo porque se han cambiado a mano
+func plannedEfforts(task Task) []Effort {
+
+	var (
+		resourceIDs = taskResourceIDs(task)
+		efforts     = task.GetEfforts()
+		byResource  = make(map[ResourceID]Effort, len(efforts))
+		startDate   = task.GetStartDate().Local()
+		endDate     = task.GetEndDate().Local()
+		planned     []Effort
+	)
+
+	if len(efforts) == 0 || len(efforts) != len(resourceIDs) {
+		return nil
+	}
+
+	for _, effort := range efforts {
+		byResource[effort.ResourceID] = effort
+	}
+
+	for _, resourceID := range resourceIDs {
+		effort, exist := byResource[resourceID]
+		if !exist || dateutil.IsLt(effort.StartDate.Local(), startDate) || dateutil.IsGt(effort.EndDate.Local(), endDate) ||
+			dateutil.IsGt(effort.StartDate.Local(), effort.EndDate.Local()) {
+			return nil
+		}
+		effort.StartDate, effort.EndDate = effort.StartDate.Local(), effort.EndDate.Local()
+		planned = append(planned, effort)
+	}
+
+	return planned
+}
+
+// taskEfforts devuelve el trabajo de cada recurso de una tarea planificada en el orden de sus recursos. Si la tarea no
+// tiene el trabajo planificado de sus recursos, o no coincide con sus fechas, cada recurso trabaja desde el comienzo
+// hasta el fin de la tarea
+func taskEfforts(task Task) []Effort {
+
+	if efforts := plannedEfforts(task); efforts != nil {
+		return efforts
+	}
+
+	var (
+		resourceIDs = taskResourceIDs(task)
+		days        = float64(task.GetDuration())
+		efforts     []Effort
+	)
+
+	if task.GetMaxTeamSize() > 1 && len(resourceIDs) > 0 {
+		days /= float64(len(resourceIDs))
+	}
+
+	for _, resourceID := range resourceIDs {
+		efforts = append(efforts, Effort{
+			ResourceID: resourceID,
+			StartDate:  task.GetStartDate().Local(),
+			EndDate:    task.GetEndDate().Local(),
+			Days:       days,
+		})
+	}
+
+	return efforts
+}
+
+// containsResourceID devuelve True si el recurso está en la lista
+func containsResourceID(resourceIDs []ResourceID, resourceID ResourceID) bool {
+	for _, id := range resourceIDs {
+		if id == resourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// newPlanCalendar crea el calendario efectivo de un plan: su calendario laboral más los festivos
+func newPlanCalendar(plan ProjectPlan) *workCalendar {
+	return newWorkCalendar(plan.GetCalendar(), plan.GetFeastDays())
+}
+
+// newResourceCalendar crea el calendario efectivo de un recurso: su calendario laboral, o el del plan si no tiene,
+// más los festivos del plan y las vacaciones del recurso
+func newResourceCalendar(plan ProjectPlan, resource Resource) *workCalendar {
+	calendar := resource.GetCalendar()
+	if calendar == nil {
+		calendar = plan.GetCalendar()
+	}
+	var unavailabilities []Holidays
+	for _, unavailability := range resource.GetUnavailabilities() {
+		unavailabilities = append(unavailabilities, unavailability)
+	}
+	return newWorkCalendar(calendar, plan.GetFeastDays(), resource.GetHolidays(), unavailabilities)
+}
+
+// workCalendar calendario efectivo con el que se calculan los días laborables: los días de la semana en los que se
+// trabaja y todos los rangos de días no laborables que le afectan
+type workCalendar struct {
+	weekdays [7]bool
+	holidays []Holidays
+}
+
+// newWorkCalendar crea un calendario efectivo a partir de un calendario y de otros rangos de días no laborables como
+// festivos o vacaciones. Si el calendario es nil o no tiene días laborables se trabaja de lunes a viernes
+func newWorkCalendar(calendar Calendar, holidays ...[]Holidays) *workCalendar {
+
+	var c = &workCalendar{}
+
+	if calendar != nil {
+		for _, weekday := range calendar.GetWorkingWeekdays() {
+			c.weekdays[weekday] = true
+		}
+		c.holidays = append(c.holidays, calendar.GetNonWorkingDays()...)
+	}
+
+	if c.weekdays == [7]bool{} {
+		for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+			c.weekdays[weekday] = true
+		}
+	}
+
+	for _, h := range holidays {
+		c.holidays = append(c.holidays, h...)
+	}
+
+	return c
+}
+
+// shareWorkingWeekday devuelve True si todos los calendarios tienen algún día de la semana laborable en común
+func shareWorkingWeekday(calendars []*workCalendar) bool {
+	for weekday := range calendars[0].weekdays {
+		shared := true
+		for _, c := range calendars {
+			shared = shared && c.weekdays[weekday]
+		}
+		if shared {
+			return true
+		}
+	}
+	return false
+}
+
+// isLaborableDay devuelve True si el día que recibe como parámetro es laborable
+func (c *workCalendar) isLaborableDay(day time.Time) bool {
+
+	// Si el día que recibimos no es uno de los días laborables de la semana devuelve False
+	if !c.weekdays[day.Weekday()] {
+		return false
+	}
+
+	// Si el día que recibimos está entre los rangos de vacaciones o días de fiesta devuelve False
+	for _, h := range c.holidays {
+		if dateutil.IsBetween(day, h.GetFrom(), h.GetTo()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// laborableDays Devuelve los días laborables que hay entre dos fechas, incluidas ambas.
+func (c *workCalendar) laborableDays(from time.Time, to time.Time) uint {
+	var days uint
+	date := from
+	for dateutil.IsLte(date, to) {
+		if c.isLaborableDay(date) {
+			days++
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+// laborableDate devuelve una fecha laborable a partir de una fecha sumando o restando los días que recibe como parámetro.
+func (c *workCalendar) laborableDate(from time.Time, days int) time.Time {
 
 	var (
 		increment int
@@ -24,58 +274,145 @@ func CalculateLaborableDate(from time.Time, days int, holidays []Holidays) time.
 	for days != 0 {
 		// Si days es < 0 restará uno, si es > 0 debe sumar 1
 		date = date.AddDate(0, 0, increment*(-1))
-		if IsLaborableDay(date, holidays) {
+		if c.isLaborableDay(date) {
 			days += increment
 		}
 	}
 	return date
 }
 
-// CalculateLaborableDays Devuelve los días laborables que hay entre dos fechas, incluidas ambas.
-func CalculateLaborableDays(from time.Time, to time.Time, holidays []Holidays) uint {
-	var days uint
-	date := from
-	for dateutil.IsLte(date, to) {
-		if IsLaborableDay(date, holidays) {
-			days++
+// taskSuccessors devuelve las tareas a las que bloquea cada tarea, tanto las de su blocksTo como las que la tienen en
+// su blocksBy. Primero van las de blocksTo, de manera que si las dependencias aparecen en las dos tareas el orden es el
+// de blocksTo
+func taskSuccessors(tasks []Task) map[TaskID][]TaskID {
+
+	var (
+		successors = make(map[TaskID][]TaskID, len(tasks))
+		linked     = make(map[[2]TaskID]bool)
+	)
+
+	addSuccessor := func(from TaskID, to TaskID) {
+		if !linked[[2]TaskID{from, to}] {
+			linked[[2]TaskID{from, to}] = true
+			successors[from] = append(successors[from], to)
 		}
-		date = date.AddDate(0, 0, 1)
 	}
-	return days
+
+	for _, task := range tasks {
+		for _, dep := range task.GetBlocksTo() {
+			addSuccessor(task.GetID(), dep.GetTaskID())
+		}
+	}
+	for _, task := range tasks {
+		for _, dep := range task.GetBlocksBy() {
+			addSuccessor(dep.GetTaskID(), task.GetID())
+		}
+	}
+
+	return successors
 }
 
-// IsLaborableDay devuelve True si el día que recibe como parámetro es laborable
-func IsLaborableDay(day time.Time, feastDays []Holidays) bool {
+// linkedDependency dependencia declarada solo en la otra tarea del enlace, vista desde esta tarea: su ID es el de la
+// tarea que la declara y su tipo y desfase los de la dependencia original
+type linkedDependency struct {
+	TaskDependency
+	taskID TaskID
+}
 
-	// Si el día que recibimos es sábado o domingo devuelve False
-	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
-		return false
+// GetTaskID ID de la tarea que declara la dependencia
+func (d linkedDependency) GetTaskID() TaskID {
+	return d.taskID
+}
+
+// taskDependencies devuelve las dependencias de cada tarea con las tareas que la bloquean y con las tareas a las que
+// bloquea, tanto las que declara ella como las que solo están declaradas en la otra tarea del enlace. Si una dependencia
+// está en las dos tareas se usa la de la propia tarea
+func taskDependencies(tasks []Task) (map[TaskID][]TaskDependency, map[TaskID][]TaskDependency) {
+
+	var (
+		blocksBy = make(map[TaskID][]TaskDependency)
+		blocksTo = make(map[TaskID][]TaskDependency)
+	)
+
+	for _, task := range tasks {
+		blocksBy[task.GetID()] = append(blocksBy[task.GetID()], task.GetBlocksBy()...)
+		blocksTo[task.GetID()] = append(blocksTo[task.GetID()], task.GetBlocksTo()...)
 	}
 
-	// Si el día que recibimos está entre los rangos de vacaciones o días de fiesta devuelve False
-	for _, h := range feastDays {
-		if dateutil.IsBetween(day, h.GetFrom(), h.GetTo()) {
-			return false
+	for _, task := range tasks {
+		for _, dep := range task.GetBlocksTo() {
+			if findDependency(blocksBy[dep.GetTaskID()], task.GetID()) == nil {
+				blocksBy[dep.GetTaskID()] = append(blocksBy[dep.GetTaskID()], linkedDependency{TaskDependency: dep, taskID: task.GetID()})
+			}
+		}
+		for _, dep := range task.GetBlocksBy() {
+			if findDependency(blocksTo[dep.GetTaskID()], task.GetID()) == nil {
+				blocksTo[dep.GetTaskID()] = append(blocksTo[dep.GetTaskID()], linkedDependency{TaskDependency: dep, taskID: task.GetID()})
+			}
 		}
 	}
 
-	return true
+	return blocksBy, blocksTo
+}
+
+// topologicalSort ordena los IDs de tareas de manera que cada tarea aparezca después de todas sus predecesoras.
+// Entre las tareas que están listas para ser ordenadas elige primero la menor según la función less.
+// Si hay dependencias circulares devuelve false
+func topologicalSort(taskIDs []TaskID, successors map[TaskID][]TaskID, less func(a, b TaskID) bool) ([]TaskID, bool) {
+
+	var (
+		inDegree = make(map[TaskID]int, len(taskIDs))
+		ready    []TaskID
+		sorted   = make([]TaskID, 0, len(taskIDs))
+	)
+
+	for _, taskID := range taskIDs {
+		for _, succ := range successors[taskID] {
+			inDegree[succ]++
+		}
+	}
+
+	for _, taskID := range taskIDs {
+		if inDegree[taskID] == 0 {
+			ready = append(ready, taskID)
+		}
+	}
+
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool {
+			return less(ready[i], ready[j])
+		})
+
+		taskID := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, taskID)
+
+		for _, succ := range successors[taskID] {
+			inDegree[succ]--
+			if inDegree[succ] == 0 {
+				ready = append(ready, succ)
+			}
+		}
+	}
+
+	return sorted, len(sorted) == len(taskIDs)
 }
 
 // Crea un Message de tipo Error solo con un mensaje de texto
-func newTextError(message string, params ...interface{}) *Error {
-	return newError(message, nil, params...)
+func newTextError(kind error, message string, params ...interface{}) *Error {
+	return newError(kind, message, nil, params...)
 }
 
 // newError Crea un objeto de tipo Error
-func newError(message string, tasks []TaskID, params ...interface{}) *Error {
+func newError(kind error, message string, tasks []TaskID, params ...interface{}) *Error {
 	return &Error{
 		Message: fmt.Errorf(message, params...),
 		Tasks:   tasks,
+		Kind:    kind,
 	}
 }
 
 // Version versión de la librería gplan
 func Version() string {
-	return "1.18_2"
+	return "1.18_3"
 }
@@ -0,0 +1,96 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Constraint", func() {
+
+	var (
+		tasks     []*Task
+		resources []*Resource
+	)
+
+	BeforeEach(func() {
+		tasks = []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 5),
+			NewTask("Tarea2", "Summary", "backend", 20, 3),
+			NewTask("Tarea3", "Summary", "backend", 30, 2),
+			NewTask("Tarea4", "Summary", "backend", 40, 1),
+		}
+
+		tasks[1].SetConstraint(gplan.StartNoEarlierThan, parseDate("2021-06-09"))
+		tasks[2].SetConstraint(gplan.MustStartOn, parseDate("2021-06-14"))
+		tasks[3].SetConstraint(gplan.FinishNoLaterThan, parseDate("2021-06-14"))
+
+		resources = []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-06-07"), nil),
+		}
+	})
+
+	When("las restricciones de fecha se pueden cumplir", func() {
+
+		It("El plan debe respetarlas", func() {
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			// Tarea4 se asigna al recurso con el que termina antes de su fecha límite
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-11 ahg",
+				"2021-06-09 2021-06-11 cslopez",
+				"2021-06-14 2021-06-15 ahg",
+				"2021-06-14 2021-06-14 cslopez",
+			})
+		})
+
+		It("Una fecha de comienzo ya planificada no debe usarse como restricción", func() {
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			tasks[0].StartDate = parseDate("2021-06-21")
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			assignment, _ := schedule.GetAssignment("Tarea1")
+			Expect(assignment.StartDate).Should(Equal(parseDate("2021-06-07")))
+		})
+	})
+
+	When("hay restricciones de fecha que no se pueden cumplir", func() {
+
+		It("Debe devolver un error con todas las tareas que no las cumplen", func() {
+			tasks = append(tasks,
+				NewTask("Tarea5", "Summary", "backend", 50, 2),
+				NewTask("Tarea6", "Summary", "backend", 60, 10),
+			)
+			tasks[4].SetConstraint(gplan.StartNoLaterThan, parseDate("2021-06-08"))
+			tasks[5].SetConstraint(gplan.FinishNoLaterThan, parseDate("2021-06-10"))
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("no se pueden cumplir las restricciones de fecha de las siguientes tareas")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea5", "Tarea6"}))
+			Expect(plan.Tasks[0].ResourceID).Should(BeNil())
+		})
+	})
+
+	When("hay restricciones de fecha sin fecha", func() {
+
+		It("Debe devolver un error", func() {
+			tasks[0].SetConstraint(gplan.StartNoLaterThan, parseDate("0001-01-01"))
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas tienen una restricción de fecha desconocida o sin fecha")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+		})
+	})
+
+})
@@ -0,0 +1,320 @@
+package gplan
+
+import (
+	"sort"
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// TaskFloat datos del cálculo del camino crítico de una tarea planificada
+type TaskFloat struct {
+	// ID de la tarea
+	TaskID TaskID
+	// Fecha de comienzo más temprana, coincide con la planificada
+	EarlyStart time.Time
+	// Fecha de fin más temprana, coincide con la planificada
+	EarlyFinish time.Time
+	// Fecha de comienzo más tardía sin retrasar el fin del proyecto
+	LateStart time.Time
+	// Fecha de fin más tardía sin retrasar el fin del proyecto
+	LateFinish time.Time
+	// Días laborables que se puede retrasar la tarea sin retrasar el fin del proyecto
+	TotalFloat int
+	// Días laborables que se puede retrasar la tarea sin retrasar a ninguna de sus sucesoras
+	FreeFloat int
+	// Indica si la tarea pertenece al camino crítico
+	Critical bool
+}
+
+// CriticalPathInfo resultado del cálculo del camino crítico de una planificación
+type CriticalPathInfo struct {
+	// Holguras de cada tarea
+	Tasks map[TaskID]*TaskFloat
+	// Tareas del camino crítico ordenadas por fecha de comienzo
+	CriticalPath []TaskID
+}
+
+// criticalPathLink enlace entre dos tareas para el cálculo del camino crítico. Puede venir de una dependencia o de que
+// ambas tareas estén asignadas al mismo recurso
+type criticalPathLink struct {
+	to      TaskID
+	depType DependencyType
+	lag     int
+}
+
+// CriticalPath calcula las fechas más tempranas y más tardías, las holguras y el camino crítico de una planificación
+// ya realizada. Además de las dependencias entre tareas tiene en cuenta que dos tareas consecutivas asignadas al mismo
+// recurso no pueden solaparse, ya que es una restricción que introduce la asignación de recursos.
+// Las fechas se manejan como instantes en días laborables: el comienzo de una tarea es el número de días laborables
+// anteriores a su fecha de comienzo y el fin el número de días laborables hasta su fecha de fin incluida.
+func CriticalPath(plan ProjectPlan) (*CriticalPathInfo, *Error) {
+
+	var (
+		tasks      = plan.GetTasks()
+		calendar   = newPlanCalendar(plan)
+		tasksIndex = make(map[TaskID]Task, len(tasks))
+		taskIDs    = make([]TaskID, 0, len(tasks))
+		position   = make(map[TaskID]int, len(tasks))
+	)
+
+	if len(tasks) == 0 {
+		return nil, newTextError(ErrEmptyTaskList, "la lista de tareas a planificar está vacía")
+	}
+
+	for i, task := range tasks {
+		if !isPlanned(task) {
+			return nil, newTextError(ErrUnplannedTask, "Hay tareas sin planificar aun")
+		}
+		tasksIndex[task.GetID()] = task
+		taskIDs = append(taskIDs, task.GetID())
+		position[task.GetID()] = i
+	}
+
+	// Índice de días laborables desde el comienzo hasta el fin del plan
+	var (
+		startDate = tasks[0].GetStartDate()
+		endDate   = tasks[0].GetEndDate()
+	)
+	for _, task := range tasks {
+		if dateutil.IsLt(task.GetStartDate(), startDate) {
+			startDate = task.GetStartDate()
+		}
+		if dateutil.IsGt(task.GetEndDate(), endDate) {
+			endDate = task.GetEndDate()
+		}
+	}
+	workdays := newWorkdayIndex(startDate, endDate, calendar)
+
+	links := criticalPathLinks(tasks, tasksIndex, workdays)
+
+	successors := make(map[TaskID][]TaskID, len(tasks))
+	for taskID, taskLinks := range links {
+		for _, link := range taskLinks {
+			successors[taskID] = append(successors[taskID], link.to)
+		}
+	}
+
+	sorted, ok := topologicalSort(taskIDs, successors, func(a, b TaskID) bool {
+		return position[a] < position[b]
+	})
+	if !ok {
+		return nil, newTextError(ErrCircularDependency, "no se puede calcular el camino crítico porque hay tareas con dependencias circulares")
+	}
+
+	var (
+		earlyStart  = make(map[TaskID]int, len(tasks))
+		earlyFinish = make(map[TaskID]int, len(tasks))
+		lateStart   = make(map[TaskID]int, len(tasks))
+		lateFinish  = make(map[TaskID]int, len(tasks))
+		projectEnd  int
+	)
+
+	for _, task := range tasks {
+		earlyStart[task.GetID()] = workdays.before(task.GetStartDate())
+		earlyFinish[task.GetID()] = workdays.through(task.GetEndDate())
+		// Un hito comienza y termina en el mismo instante
+		if IsMilestone(task) {
+			earlyFinish[task.GetID()] = earlyStart[task.GetID()]
+		}
+		if earlyFinish[task.GetID()] > projectEnd {
+			projectEnd = earlyFinish[task.GetID()]
+		}
+	}
+
+	// Recorre las tareas desde el final calculando el fin más tardío que permiten sus sucesoras
+	for i := len(sorted) - 1; i >= 0; i-- {
+		taskID := sorted[i]
+		span := earlyFinish[taskID] - earlyStart[taskID]
+		finish := projectEnd
+
+		for _, link := range links[taskID] {
+			var limit int
+			switch link.depType {
+			case StartToStart:
+				limit = lateStart[link.to] - link.lag + span
+			case FinishToFinish:
+				limit = lateFinish[link.to] - link.lag
+			case StartToFinish:
+				limit = lateFinish[link.to] - link.lag + span
+			default:
+				limit = lateStart[link.to] - link.lag
+			}
+			if limit < finish {
+				finish = limit
+			}
+		}
+
+		lateFinish[taskID] = finish
+		lateStart[taskID] = finish - span
+	}
+
+	info := &CriticalPathInfo{
+		Tasks: make(map[TaskID]*TaskFloat, len(tasks)),
+	}
+
+	for _, taskID := range sorted {
+
+		// La holgura libre es lo que se puede retrasar sin mover el comienzo más temprano de ninguna sucesora
+		freeFloat := projectEnd - earlyFinish[taskID]
+		for _, link := range links[taskID] {
+			var float int
+			switch link.depType {
+			case StartToStart:
+				float = earlyStart[link.to] - (earlyStart[taskID] + link.lag)
+			case FinishToFinish:
+				float = earlyFinish[link.to] - (earlyFinish[taskID] + link.lag)
+			case StartToFinish:
+				float = earlyFinish[link.to] - (earlyStart[taskID] + link.lag)
+			default:
+				float = earlyStart[link.to] - (earlyFinish[taskID] + link.lag)
+			}
+			if float < freeFloat {
+				freeFloat = float
+			}
+		}
+
+		task := tasksIndex[taskID]
+		taskFloat := &TaskFloat{
+			TaskID:      taskID,
+			EarlyStart:  task.GetStartDate(),
+			EarlyFinish: task.GetEndDate(),
+			LateStart:   workdays.day(lateStart[taskID]),
+			LateFinish:  workdays.day(lateFinish[taskID] - 1),
+			TotalFloat:  lateStart[taskID] - earlyStart[taskID],
+			FreeFloat:   freeFloat,
+		}
+		taskFloat.Critical = taskFloat.TotalFloat <= 0
+
+		// La fecha de un hito es el comienzo de su día, por lo que su fecha más tardía es la misma al comenzar y terminar
+		if IsMilestone(task) {
+			taskFloat.LateFinish = taskFloat.LateStart
+		}
+
+		info.Tasks[taskID] = taskFloat
+		if taskFloat.Critical {
+			info.CriticalPath = append(info.CriticalPath, taskID)
+		}
+	}
+
+	// Ordena el camino crítico por fecha de comienzo manteniendo el orden topológico en caso de empate
+	sort.SliceStable(info.CriticalPath, func(i, j int) bool {
+		return earlyStart[info.CriticalPath[i]] < earlyStart[info.CriticalPath[j]]
+	})
+
+	return info, nil
+}
+
+// criticalPathLinks devuelve los enlaces de cada tarea con sus sucesoras, tanto los de las dependencias como los que se
+// producen entre dos tareas consecutivas asignadas a un mismo recurso
+func criticalPathLinks(tasks []Task, tasksIndex map[TaskID]Task, workdays *workdayIndex) map[TaskID][]criticalPathLink {
+
+	// resourceWork parte de una tarea que realiza un recurso
+	type resourceWork struct {
+		task   Task
+		effort Effort
+	}
+
+	var (
+		links      = make(map[TaskID][]criticalPathLink, len(tasks))
+		linked     = make(map[[2]TaskID]bool)
+		byResource = make(map[ResourceID][]resourceWork)
+	)
+
+	addLink := func(from TaskID, to TaskID, depType DependencyType, lag int) {
+		if linked[[2]TaskID{from, to}] {
+			return
+		}
+		linked[[2]TaskID{from, to}] = true
+		links[from] = append(links[from], criticalPathLink{to: to, depType: depType, lag: lag})
+	}
+
+	// Las dependencias de blocksBy son las que se usan al planificar por lo que tienen preferencia
+	for _, task := range tasks {
+		for _, dep := range task.GetBlocksBy() {
+			if _, exist := tasksIndex[dep.GetTaskID()]; exist {
+				addLink(dep.GetTaskID(), task.GetID(), dep.GetType(), dep.GetLag())
+			}
+		}
+	}
+	for _, task := range tasks {
+		for _, dep := range task.GetBlocksTo() {
+			if _, exist := tasksIndex[dep.GetTaskID()]; exist {
+				addLink(task.GetID(), dep.GetTaskID(), dep.GetType(), dep.GetLag())
+			}
+		}
+	}
+
+	// Dos tareas consecutivas del mismo recurso se comportan como una dependencia FinishToStart. Las tareas divisibles
+	// no, porque se pueden intercalar con otras tareas del recurso. Si la duración de una tarea se reparte entre varios
+	// recursos, cada uno puede empezar después del comienzo de la tarea o terminar antes de su fin, y el enlace lleva
+	// un desfase negativo con esos días
+	for _, task := range tasks {
+		if !IsMilestone(task) && !task.IsSplittable() {
+			for _, effort := range taskEfforts(task) {
+				byResource[effort.ResourceID] = append(byResource[effort.ResourceID], resourceWork{task: task, effort: effort})
+			}
+		}
+	}
+	for _, works := range byResource {
+		sort.SliceStable(works, func(i, j int) bool {
+			return dateutil.IsLt(works[i].effort.StartDate, works[j].effort.StartDate)
+		})
+		for i := 1; i < len(works); i++ {
+			var (
+				previous = works[i-1]
+				next     = works[i]
+				// Días que el recurso termina antes que la tarea anterior y empieza después que la siguiente
+				earlierFinish = workdays.through(previous.task.GetEndDate()) - workdays.through(previous.effort.EndDate)
+				laterStart    = workdays.before(next.effort.StartDate) - workdays.before(next.task.GetStartDate())
+			)
+			addLink(previous.task.GetID(), next.task.GetID(), FinishToStart, -(earlierFinish + laterStart))
+		}
+	}
+
+	return links
+}
+
+// workdayIndex índice de los días laborables entre dos fechas para poder operar con ellos como números enteros
+type workdayIndex struct {
+	days     []time.Time
+	calendar *workCalendar
+}
+
+// newWorkdayIndex crea el índice de días laborables entre dos fechas, incluidas ambas
+func newWorkdayIndex(from time.Time, to time.Time, calendar *workCalendar) *workdayIndex {
+	index := &workdayIndex{calendar: calendar}
+	for date := from; dateutil.IsLte(date, to); date = date.AddDate(0, 0, 1) {
+		if calendar.isLaborableDay(date) {
+			index.days = append(index.days, date)
+		}
+	}
+	return index
+}
+
+// before devuelve el número de días laborables del índice anteriores a una fecha
+func (s *workdayIndex) before(date time.Time) int {
+	return sort.Search(len(s.days), func(i int) bool {
+		return dateutil.IsGte(s.days[i], date)
+	})
+}
+
+// through devuelve el número de días laborables del índice anteriores o iguales a una fecha
+func (s *workdayIndex) through(date time.Time) int {
+	return sort.Search(len(s.days), func(i int) bool {
+		return dateutil.IsGt(s.days[i], date)
+	})
+}
+
+// day devuelve el día laborable que ocupa una posición del índice, si está fuera del índice lo calcula
+func (s *workdayIndex) day(position int) time.Time {
+	switch {
+	case len(s.days) == 0:
+		return time.Time{}
+	case position < 0:
+		return s.calendar.laborableDate(s.days[0], position)
+	case position >= len(s.days):
+		return s.calendar.laborableDate(s.days[len(s.days)-1], position-len(s.days)+1)
+	}
+	return s.days[position]
+}
@@ -0,0 +1,84 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CriticalPath", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-10"), []*Holidays{
+				NewHolidays(parseDate("2021-06-17"), parseDate("2021-06-18")),
+			}),
+			NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-05-07"), nil),
+			NewResource("David.Attrache", "David Attrache", "maquetacion", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "maquetacion", parseDate("2021-06-07"), nil),
+		}
+
+		feastDays := []*Holidays{
+			NewHolidays(parseDate("2021-06-10"), parseDate("2021-06-11")),
+			NewHolidays(parseDate("2021-07-21"), parseDate("2021-07-22")),
+		}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 30, 10),
+			NewTask("Tarea2", "Summary", "maquetacion", 50, 2),
+			NewTask("Tarea3", "Summary", "backend", 40, 1),
+			NewTask("Tarea4", "Summary", "maquetacion", 20, 20),
+			NewTask("Tarea5", "Summary", "backend", 60, 7),
+			NewTask("Tarea6", "Summary", "backend", 10, 9),
+		}
+
+		BlocksTo(tasks[3], tasks[0])
+		BlocksTo(tasks[5], tasks[2])
+		BlocksTo(tasks[5], tasks[1])
+
+		plan = NewProjectPlan("test-plan", tasks, resources, feastDays)
+		err := gplan.Planning(parseDate("2021-06-07"), plan)
+		Expect(err).Should(BeNil())
+	})
+
+	When("calculamos el camino crítico de un plan planificado", func() {
+
+		It("Debe devolver las holguras y el camino crítico", func() {
+			info, err := gplan.CriticalPath(plan)
+			Expect(err).Should(BeNil())
+
+			Expect(info.CriticalPath).Should(Equal([]gplan.TaskID{"Tarea4", "Tarea1"}))
+
+			compareFloat(info.Tasks["Tarea4"], "2021-06-07 2021-07-06 2021-06-07 2021-07-06 0 0")
+			compareFloat(info.Tasks["Tarea1"], "2021-07-07 2021-07-20 2021-07-07 2021-07-20 0 0")
+			// Tarea6 se retrasa por las tareas de su mismo recurso (Tarea3 y Tarea5)
+			compareFloat(info.Tasks["Tarea6"], "2021-06-07 2021-06-21 2021-06-28 2021-07-08 13 0")
+			compareFloat(info.Tasks["Tarea3"], "2021-06-22 2021-06-22 2021-07-09 2021-07-09 13 0")
+			compareFloat(info.Tasks["Tarea5"], "2021-06-23 2021-07-01 2021-07-12 2021-07-20 13 13")
+			compareFloat(info.Tasks["Tarea2"], "2021-06-22 2021-06-23 2021-07-19 2021-07-20 19 19")
+		})
+	})
+
+	When("el plan no está planificado", func() {
+
+		It("Debe devolver un error", func() {
+			plan.Tasks[0].ResourceID = nil
+
+			_, err := gplan.CriticalPath(plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("Hay tareas sin planificar aun")))
+		})
+	})
+
+})
+
+func compareFloat(taskFloat *gplan.TaskFloat, compare string) {
+	Expect(fmt.Sprintf("%s %s %s %s %d %d",
+		taskFloat.EarlyStart.Format("2006-01-02"), taskFloat.EarlyFinish.Format("2006-01-02"),
+		taskFloat.LateStart.Format("2006-01-02"), taskFloat.LateFinish.Format("2006-01-02"),
+		taskFloat.TotalFloat, taskFloat.FreeFloat)).Should(Equal(compare))
+}
@@ -0,0 +1,60 @@
+package gplan_test
+
+import (
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Efficiency", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("junior", "Junior", "backend", parseDate("2021-06-07"), nil),
+			NewResource("senior", "Senior", "backend", parseDate("2021-06-07"), nil),
+		}
+
+		// Hace una jornada estándar en media jornada pero maqueta al ritmo normal
+		resources[1].Efficiency = 200
+		resources[1].Skills = []*Skill{NewSkill("maquetacion", 50)}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 4),
+			NewTask("Tarea2", "Summary", "backend", 20, 4),
+			NewTask("Tarea3", "Summary", "maquetacion", 30, 2),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("los recursos tienen distinto rendimiento", func() {
+
+		It("La duración de las tareas debe ajustarse al rendimiento del recurso", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			// Tarea2 termina a la vez con los dos recursos y se queda con el primero
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-08 senior",
+				"2021-06-07 2021-06-10 junior",
+				"2021-06-09 2021-06-10 senior",
+			})
+		})
+
+		It("El avance esperado debe calcularse con la duración ajustada", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			err = gplan.Review(plan, parseDate("2021-06-08"))
+			Expect(err).Should(BeNil())
+
+			Expect(plan.Tasks[0].ExpectedProgress).Should(BeEquivalentTo(50))
+			Expect(plan.Tasks[0].ExpectedCompleteDuration).Should(BeEquivalentTo(2))
+			Expect(plan.Tasks[1].ExpectedProgress).Should(BeEquivalentTo(25))
+			Expect(plan.Tasks[1].ExpectedCompleteDuration).Should(BeEquivalentTo(1))
+		})
+	})
+
+})
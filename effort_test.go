@@ -0,0 +1,183 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// compareEfforts devuelve el trabajo de cada recurso en una tarea con el formato "recurso comienzo jornadas"
+func compareEfforts(assignment gplan.Assignment) []string {
+	var result []string
+	for _, effort := range assignment.Efforts {
+		result = append(result, fmt.Sprintf("%s %s %.1f", effort.ResourceID, effort.StartDate.Format("2006-01-02"), effort.Days))
+	}
+	return result
+}
+
+var _ = Describe("Effort", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-06-07"), nil),
+			NewResource("jmartin", "Juan Martín", "backend", parseDate("2021-06-14"), nil),
+		}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 21),
+			NewTask("Tarea2", "Summary", "backend", 20, 2),
+		}
+		tasks[0].MaxTeamSize = 3
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("la duración de una tarea se puede repartir entre varios recursos", func() {
+
+		It("Debe asignarle el equipo con el que termine antes y registrar el trabajo de cada recurso", func() {
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			// jmartin se incorpora la segunda semana
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-07 2021-06-17 ahg",
+				"Tarea2 2021-06-17 2021-06-18 jmartin",
+			}))
+
+			assignment, _ := schedule.GetAssignment("Tarea1")
+			Expect(assignment.ResourceIDs).Should(Equal([]gplan.ResourceID{"ahg", "cslopez", "jmartin"}))
+			Expect(compareEfforts(assignment)).Should(Equal([]string{
+				"ahg 2021-06-07 9.0",
+				"cslopez 2021-06-07 9.0",
+				"jmartin 2021-06-14 3.0",
+			}))
+
+			var timeline []string
+			for _, booking := range schedule.GetResourceTimeline("jmartin") {
+				timeline = append(timeline, fmt.Sprintf("%s %s %s", booking.TaskID,
+					booking.StartDate.Format("2006-01-02"), booking.EndDate.Format("2006-01-02")))
+			}
+			Expect(timeline).Should(Equal([]string{
+				"Tarea1 2021-06-14 2021-06-16",
+				"Tarea2 2021-06-17 2021-06-18",
+			}))
+		})
+
+		It("No debe superar el tamaño máximo del equipo", func() {
+			plan.Tasks[0].MaxTeamSize = 2
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			assignment, _ := schedule.GetAssignment("Tarea1")
+			Expect(assignment.EndDate).Should(Equal(parseDate("2021-06-21")))
+			Expect(compareEfforts(assignment)).Should(Equal([]string{
+				"ahg 2021-06-07 11.0",
+				"cslopez 2021-06-07 10.0",
+			}))
+		})
+
+		It("Debe quedarse con el equipo formado si un recurso deja de estar disponible antes de que termine la tarea", func() {
+			// jmartin se va el martes y solo no podría terminar la tarea
+			plan.Resources[2].AvailableFrom = parseDate("2021-06-07")
+			plan.Resources[2].AvailableUntil = parseDate("2021-06-08")
+			plan.Tasks[0].Duration = 9
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			assignment, _ := schedule.GetAssignment("Tarea1")
+			Expect(assignment.EndDate).Should(Equal(parseDate("2021-06-11")))
+			Expect(compareEfforts(assignment)).Should(Equal([]string{
+				"ahg 2021-06-07 5.0",
+				"cslopez 2021-06-07 4.0",
+			}))
+		})
+
+		It("El avance esperado debe sumar el trabajo de todo el equipo", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+			Expect(plan.Tasks[0].ResourceIDs).Should(HaveLen(3))
+
+			err = gplan.Review(plan, parseDate("2021-06-15"))
+			Expect(err).Should(BeNil())
+
+			// 13 de 21 jornadas
+			Expect(plan.Tasks[0].ExpectedCompleteDuration).Should(BeEquivalentTo(13))
+			Expect(plan.Tasks[0].ExpectedProgress).Should(BeEquivalentTo(61))
+		})
+
+		It("La tarea debe guardar el trabajo de cada recurso y el resto de cálculos deben usarlo", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareEfforts(gplan.Assignment{Efforts: plan.Tasks[0].Efforts})).Should(Equal([]string{
+				"ahg 2021-06-07 9.0",
+				"cslopez 2021-06-07 9.0",
+				"jmartin 2021-06-14 3.0",
+			}))
+
+			// jmartin termina su parte de Tarea1 antes de empezar Tarea2 aunque Tarea1 termina después
+			Expect(gplan.Verify(plan)).Should(BeEmpty())
+
+			_, moves, gerr := gplan.LevelResources(plan)
+			Expect(gerr).Should(BeNil())
+			Expect(moves).Should(BeEmpty())
+
+			info, gerr := gplan.CriticalPath(plan)
+			Expect(gerr).Should(BeNil())
+			for _, taskFloat := range info.Tasks {
+				Expect(taskFloat.TotalFloat).Should(BeNumerically(">=", 0))
+				Expect(taskFloat.FreeFloat).Should(BeNumerically(">=", 0))
+			}
+		})
+
+		It("Redistribuir un plan con una tarea repartida no debe mover ninguna tarea", func() {
+			// Todos están disponibles desde el principio y uno de ellos termina su parte un día antes que la tarea
+			plan.Resources[2].AvailableFrom = parseDate("2021-06-07")
+			plan.Tasks[0].Duration = 20
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-15 ahg",
+				"2021-06-15 2021-06-16 jmartin",
+			})
+			Expect(compareEfforts(gplan.Assignment{Efforts: plan.Tasks[0].Efforts})).Should(Equal([]string{
+				"ahg 2021-06-07 7.0",
+				"cslopez 2021-06-07 7.0",
+				"jmartin 2021-06-07 6.0",
+			}))
+			Expect(gplan.Verify(plan)).Should(BeEmpty())
+
+			_, moves, gerr := gplan.LevelResources(plan)
+			Expect(gerr).Should(BeNil())
+			Expect(moves).Should(BeEmpty())
+
+			info, gerr := gplan.CriticalPath(plan)
+			Expect(gerr).Should(BeNil())
+			for _, taskFloat := range info.Tasks {
+				Expect(taskFloat.TotalFloat).Should(BeNumerically(">=", 0))
+			}
+		})
+	})
+
+	When("una tarea reparte su duración y necesita varios recursos a la vez", func() {
+
+		It("Debe devolver un error", func() {
+			plan.Tasks[0].ResourceDemands = []*ResourceDemand{NewResourceDemand("backend", 2)}
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas tienen demandas de recursos y reparten su duración entre varios recursos")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+		})
+	})
+
+})
@@ -0,0 +1,88 @@
+package gplan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Tipos de error. Cada Error lleva uno de ellos en Kind y se pueden comprobar con errors.Is, por ejemplo
+// errors.Is(err, gplan.ErrCircularDependency)
+var (
+	// ErrEmptyTaskList el plan no tiene tareas
+	ErrEmptyTaskList = errors.New("la lista de tareas está vacía")
+	// ErrEmptyResourceList el plan no tiene recursos
+	ErrEmptyResourceList = errors.New("la lista de recursos está vacía")
+	// ErrInvalidPlan el plan tiene datos incorrectos, por ejemplo una regla de prioridad desconocida
+	ErrInvalidPlan = errors.New("el plan tiene datos incorrectos")
+	// ErrInvalidTask hay tareas con datos incorrectos, por ejemplo un número de orden inferior a 1 o un ID repetido
+	ErrInvalidTask = errors.New("hay tareas con datos incorrectos")
+	// ErrInvalidResource hay recursos con datos incorrectos, por ejemplo una dedicación que no está entre 1 y 100
+	ErrInvalidResource = errors.New("hay recursos con datos incorrectos")
+	// ErrUnknownResource hay tareas que necesitan o tienen asignado un recurso que no existe
+	ErrUnknownResource = errors.New("hay tareas con un recurso que no existe")
+	// ErrResourceCannotDoTask hay tareas que necesitan un recurso que no puede realizarlas
+	ErrResourceCannotDoTask = errors.New("hay tareas con un recurso que no puede realizarlas")
+	// ErrUnusedResource hay recursos que no pueden realizar ninguna tarea
+	ErrUnusedResource = errors.New("hay recursos que no pueden realizar ninguna tarea")
+	// ErrMissingResourceType no hay recursos del tipo que necesitan algunas tareas
+	ErrMissingResourceType = errors.New("no hay recursos del tipo que necesitan las tareas")
+	// ErrNotEnoughResources no hay suficientes recursos para las tareas que necesitan varios a la vez
+	ErrNotEnoughResources = errors.New("no hay suficientes recursos para las tareas")
+	// ErrUnknownDependency hay tareas que bloquean o están bloqueadas por tareas que no existen
+	ErrUnknownDependency = errors.New("hay dependencias con tareas que no existen")
+	// ErrInvalidDependency hay dependencias de un tipo desconocido o que no coinciden en las dos tareas
+	ErrInvalidDependency = errors.New("hay dependencias incorrectas")
+	// ErrCircularDependency hay tareas que se bloquean a sí mismas a través de su cadena de dependencias
+	ErrCircularDependency = errors.New("hay dependencias circulares")
+	// ErrUnplannedTask hay tareas sin planificar en un plan que se tiene que haber planificado
+	ErrUnplannedTask = errors.New("hay tareas sin planificar")
+	// ErrUnmetConstraint no se pueden cumplir las restricciones de fecha de algunas tareas
+	ErrUnmetConstraint = errors.New("no se pueden cumplir las restricciones de fecha")
+	// ErrUnschedulable hay tareas que no se pueden planificar, por ejemplo porque sus recursos dejan de estar
+	// disponibles antes de terminarlas o porque no se pueden terminar antes de la fecha límite
+	ErrUnschedulable = errors.New("hay tareas que no se pueden planificar")
+)
+
+// Error devuelve el mensaje del error seguido de las tareas y los recursos afectados
+func (e *Error) Error() string {
+
+	var message strings.Builder
+
+	if e.Message != nil {
+		message.WriteString(e.Message.Error())
+	} else if e.Kind != nil {
+		message.WriteString(e.Kind.Error())
+	}
+
+	if len(e.Tasks) > 0 {
+		var ids []string
+		for _, id := range e.Tasks {
+			ids = append(ids, string(id))
+		}
+		fmt.Fprintf(&message, ": %s", strings.Join(ids, ", "))
+	}
+	if len(e.Resources) > 0 {
+		var ids []string
+		for _, id := range e.Resources {
+			ids = append(ids, string(id))
+		}
+		fmt.Fprintf(&message, " (recursos: %s)", strings.Join(ids, ", "))
+	}
+
+	return message.String()
+}
+
+// Unwrap devuelve el tipo del error para que se pueda comprobar con errors.Is
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Err devuelve el error como un error de Go, o nil si no hay error. Las funciones de la librería devuelven *Error, que
+// si se asigna directamente a una variable de tipo error no es nil aunque no haya error
+func (e *Error) Err() error {
+	if e == nil {
+		return nil
+	}
+	return e
+}
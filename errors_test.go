@@ -0,0 +1,75 @@
+package gplan_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 3),
+			NewTask("Tarea2", "Summary", "backend", 20, 3),
+		}
+
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("la planificación devuelve un error", func() {
+
+		It("Debe poder comprobarse su tipo con errors.Is y errors.As aunque se envuelva", func() {
+			BlocksTo(plan.Tasks[0], plan.Tasks[1])
+			BlocksTo(plan.Tasks[1], plan.Tasks[0])
+
+			_, gerr := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			err := fmt.Errorf("no se ha podido planificar: %w", gerr.Err())
+
+			Expect(errors.Is(err, gplan.ErrCircularDependency)).Should(BeTrue())
+			Expect(errors.Is(err, gplan.ErrEmptyTaskList)).Should(BeFalse())
+
+			var target *gplan.Error
+			Expect(errors.As(err, &target)).Should(BeTrue())
+			Expect(target.Message).Should(Equal(fmt.Errorf("Tarea1 contiene una referencia circular en su cadena de dependencias")))
+			Expect(target.Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+		})
+
+		It("Su mensaje debe incluir las tareas y los recursos afectados", func() {
+			plan.Tasks[0].Order = 0
+			plan.Resources = append(plan.Resources, NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil))
+
+			_, gerr := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(gerr.Error()).Should(Equal("los siguientes recursos tienen el ID repetido (recursos: ahg)"))
+			Expect(errors.Is(gerr, gplan.ErrInvalidResource)).Should(BeTrue())
+
+			plan.Resources = plan.Resources[:1]
+			_, gerr = gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(gerr.Error()).Should(Equal("las siguientes tareas tienen un orden inferior a 1: Tarea1"))
+			Expect(errors.Is(gerr, gplan.ErrInvalidTask)).Should(BeTrue())
+		})
+
+		It("Debe poder comprobarse el tipo de los errores de la revisión", func() {
+			gerr := gplan.Review(plan, parseDate("2021-06-07"))
+			Expect(errors.Is(gerr.Err(), gplan.ErrUnplannedTask)).Should(BeTrue())
+		})
+	})
+
+	When("no hay error", func() {
+
+		It("Err debe devolver nil", func() {
+			gerr := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(gerr.Err()).Should(BeNil())
+		})
+	})
+
+})
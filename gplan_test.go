@@ -59,24 +59,6 @@ var _ = Describe("gplan", func() {
 			})
 		})
 
-		When("hay tareas con una duración < 1", func() {
-
-			It("Debe devolver un error", func() {
-				plan := NewProjectPlan("test", []*Task{
-					NewTask("Task-2", "summary Task-2", "maquetación", 100, 1),
-					NewTask("Task-1", "summary Task-1", "maquetación", 100, 0)},
-					[]*Resource{{}},
-					nil)
-
-				err := gplan.Planning(time.Now(), plan)
-
-				Expect(err).ShouldNot(BeNil())
-				Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas tienen la duración inferior a un día")))
-				Expect(len(err.Tasks)).Should(Equal(1))
-				Expect(err.Tasks[0]).Should(BeEquivalentTo("Task-1"))
-			})
-		})
-
 		When("hay tareas con un orden < 1", func() {
 
 			It("Debe devolver un error", func() {
@@ -118,7 +100,7 @@ var _ = Describe("gplan", func() {
 
 		When("no hay tareas del tipo necesario para los tipos de recursos que lleregan en la lista de recursos", func() {
 
-			It("Debe devolver un error", func() {
+			It("Debe planificar y devolver un aviso", func() {
 
 				plan := NewProjectPlan("test",
 					[]*Task{
@@ -126,13 +108,15 @@ var _ = Describe("gplan", func() {
 					},
 					[]*Resource{
 						NewResource("ahg", "Antonio Hueso", "backend", time.Now(), nil),
+						NewResource("Noemi", "Noe Medina", "maquetación", time.Now(), nil),
 					},
 					nil)
 
-				err := gplan.Planning(time.Now(), plan)
+				schedule, err := gplan.PlanSchedule(time.Now(), plan)
 
-				Expect(err).ShouldNot(BeNil())
-				Expect(err.Message).Should(Equal(fmt.Errorf("no existen tareas para el recurso ahg de tipo backend")))
+				Expect(err).Should(BeNil())
+				Expect(schedule.GetWarnings()).Should(HaveLen(1))
+				Expect(schedule.GetWarnings()[0].Message).Should(Equal(fmt.Errorf("no existen tareas para el recurso ahg de tipo backend")))
 			})
 		})
 
@@ -175,6 +159,28 @@ var _ = Describe("gplan", func() {
 			})
 		})
 
+		When("hay referencias circulares solo en las tareas que las bloquean", func() {
+
+			It("Debe devolver un error", func() {
+				task1 := NewTaskWithBlocks("Task-1", "summary Task-1", "backend", 10, 1, nil, []*TaskDependency{NewTaskDependency("Task-2")})
+				task2 := NewTaskWithBlocks("Task-2", "summary Task-2", "backend", 20, 2, nil, []*TaskDependency{NewTaskDependency("Task-1")})
+				task3 := NewTask("Task-3", "summary Task-3", "backend", 30, 2)
+
+				plan := NewProjectPlan("test",
+
+					[]*Task{task1, task2, task3},
+					[]*Resource{NewResource("ahg", "Antonio Hueso", "backend", time.Now(), nil)},
+					nil)
+
+				schedule, err := gplan.PlanSchedule(time.Now(), plan)
+
+				Expect(schedule).Should(BeNil())
+				Expect(err).ShouldNot(BeNil())
+				Expect(err.Kind).Should(Equal(gplan.ErrCircularDependency))
+				Expect(err.Message).Should(Equal(fmt.Errorf("Task-1 contiene una referencia circular en su cadena de dependencias")))
+			})
+		})
+
 		When("hay tareas que bloquean a tareas que no existen en la lista de tareas", func() {
 
 			It("Debe devolver un error", func() {
@@ -195,11 +201,13 @@ var _ = Describe("gplan", func() {
 			})
 		})
 
-		When("hay tareas que bloquean a otras cuyo orden es inferior", func() {
+		When("hay dependencias cuyo tipo no coincide con el de su recíproca", func() {
 
 			It("Debe devolver un error", func() {
-				task1 := NewTaskWithBlocks("Task-1", "summary Task-1", "backend", 20, 1, []*TaskDependency{NewTaskDependency("Task-2")}, nil)
-				task2 := NewTaskWithBlocks("Task-2", "summary Task-2", "backend", 10, 2, nil, nil)
+				task1 := NewTaskWithBlocks("Task-1", "summary Task-1", "backend", 10, 1,
+					[]*TaskDependency{NewTaskDependencyWithType("Task-2", gplan.StartToStart, 0)}, nil)
+				task2 := NewTaskWithBlocks("Task-2", "summary Task-2", "backend", 20, 2,
+					nil, []*TaskDependency{NewTaskDependencyWithType("Task-1", gplan.FinishToStart, 0)})
 
 				plan := NewProjectPlan("test",
 
@@ -208,12 +216,31 @@ var _ = Describe("gplan", func() {
 					nil)
 				err := gplan.Planning(time.Now(), plan)
 				Expect(err).ShouldNot(BeNil())
-				Expect(err.Message).Should(Equal(fmt.Errorf("tareas bloqueadas por tareas con un orden superior")))
+				Expect(err.Message).Should(Equal(fmt.Errorf("hay tareas cuyas dependencias no coinciden en tipo o desfase con las de la tarea que las bloquea")))
 				Expect(len(err.Tasks)).Should(Equal(1))
 				Expect(err.Tasks[0]).Should(BeEquivalentTo("Task-2"))
 			})
 		})
 
+		When("hay dependencias de un tipo desconocido", func() {
+
+			It("Debe devolver un error", func() {
+				task1 := NewTask("Task-1", "summary Task-1", "backend", 10, 1)
+				task2 := NewTask("Task-2", "summary Task-2", "backend", 20, 2)
+				BlocksToWithType(task1, task2, gplan.DependencyType(10), 0)
+
+				plan := NewProjectPlan("test",
+
+					[]*Task{task1, task2},
+					[]*Resource{NewResource("ahg", "Antonio Hueso", "backend", time.Now(), nil)},
+					nil)
+				err := gplan.Planning(time.Now(), plan)
+				Expect(err).ShouldNot(BeNil())
+				Expect(err.Message).Should(Equal(fmt.Errorf("hay tareas con dependencias de un tipo desconocido")))
+				Expect(err.Tasks).Should(ConsistOf(BeEquivalentTo("Task-1"), BeEquivalentTo("Task-2")))
+			})
+		})
+
 	})
 
 	Describe("New - Creación", func() {
@@ -323,6 +350,128 @@ var _ = Describe("gplan", func() {
 			})
 		})
 
+		When("Creamos un plan con tareas que bloquean a otras cuyo orden es inferior", func() {
+
+			It("Debe planificar primero las tareas que bloquean", func() {
+				task1 := NewTaskWithBlocks("Task-1", "summary Task-1", "backend", 20, 1, []*TaskDependency{NewTaskDependency("Task-2")}, nil)
+				task2 := NewTaskWithBlocks("Task-2", "summary Task-2", "backend", 10, 2, nil, []*TaskDependency{NewTaskDependency("Task-1")})
+				task3 := NewTaskWithBlocks("Task-3", "summary Task-3", "backend", 15, 1, nil, nil)
+
+				plan := NewProjectPlan("test",
+
+					[]*Task{task1, task2, task3},
+					[]*Resource{NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil)},
+					nil)
+				err := gplan.Planning(parseDate("2021-06-07"), plan)
+				Expect(err).Should(BeNil())
+
+				// Task-3 no tiene bloqueos y tiene menor orden que Task-1, por lo que se planifica la primera
+				comparePlan(plan.Tasks, []string{
+					"2021-06-09 2021-06-10 ahg",
+					"2021-06-07 2021-06-07 ahg",
+					"2021-06-08 2021-06-08 ahg",
+				})
+			})
+		})
+
+		When("Creamos un plan con dependencias de distintos tipos y desfases", func() {
+
+			It("El plan debe ser igual al siguiente plan", func() {
+
+				tasks := []*Task{
+					NewTask("Tarea1", "Summary", "backend", 10, 5),
+					NewTask("Tarea2", "Summary", "qa", 20, 3),
+					NewTask("Tarea3", "Summary", "qa", 30, 2),
+					NewTask("Tarea4", "Summary", "maquetacion", 40, 2),
+					NewTask("Tarea5", "Summary", "backend", 50, 1),
+				}
+
+				// QA comienza dos días después de que comience desarrollo
+				BlocksToWithType(tasks[0], tasks[1], gplan.StartToStart, 2)
+				// No puede terminar hasta un día después de que termine desarrollo
+				BlocksToWithType(tasks[0], tasks[2], gplan.FinishToFinish, 1)
+				// Puede comenzar un día antes de que termine desarrollo
+				BlocksToWithType(tasks[0], tasks[3], gplan.FinishToStart, -1)
+				// Debe esperar dos días después de que termine desarrollo
+				BlocksToWithType(tasks[0], tasks[4], gplan.FinishToStart, 2)
+
+				resources := []*Resource{
+					NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+					NewResource("qa1", "QA 1", "qa", parseDate("2021-06-07"), nil),
+					NewResource("qa2", "QA 2", "qa", parseDate("2021-06-07"), nil),
+					NewResource("Noemi", "Noe Medina", "maquetacion", parseDate("2021-06-07"), nil),
+				}
+
+				plan := NewProjectPlan("test-plan", tasks, resources, nil)
+				err := gplan.Planning(parseDate("2021-06-07"), plan)
+
+				Expect(err).Should(BeNil())
+				comparePlan(plan.Tasks, []string{
+					"2021-06-07 2021-06-11 ahg",
+					"2021-06-09 2021-06-11 qa1",
+					"2021-06-11 2021-06-14 qa2",
+					"2021-06-11 2021-06-14 Noemi",
+					"2021-06-16 2021-06-16 ahg",
+				})
+				Expect(plan.StartDate.Format("2006-01-02")).Should(Equal("2021-06-07"))
+				Expect(plan.EndDate.Format("2006-01-02")).Should(Equal("2021-06-16"))
+			})
+		})
+
+		When("Creamos un plan con una tarea repartida entre varios recursos que excluye a uno de ellos", func() {
+
+			It("Debe repartirla entre los recursos que no están excluidos", func() {
+
+				tasks := []*Task{
+					NewTask("Tarea1", "Summary", "backend", 10, 6),
+				}
+				tasks[0].MaxTeamSize = 3
+				tasks[0].ExcludedResourceIDs = []gplan.ResourceID{"jmartin"}
+
+				resources := []*Resource{
+					NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+					NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-06-07"), nil),
+					NewResource("jmartin", "Juan Martín", "backend", parseDate("2021-06-07"), nil),
+				}
+
+				plan := NewProjectPlan("test-plan", tasks, resources, nil)
+				err := gplan.Planning(parseDate("2021-06-07"), plan)
+
+				Expect(err).Should(BeNil())
+				comparePlan(plan.Tasks, []string{
+					"2021-06-07 2021-06-09 ahg",
+				})
+				Expect(plan.Tasks[0].ResourceIDs).Should(Equal([]gplan.ResourceID{"ahg", "cslopez"}))
+			})
+		})
+
+		When("Creamos un plan con una tarea repartida entre varios recursos que requiere uno de ellos", func() {
+
+			It("Debe asignársela solo al recurso requerido", func() {
+
+				tasks := []*Task{
+					NewTask("Tarea1", "Summary", "backend", 10, 6),
+				}
+				tasks[0].MaxTeamSize = 3
+				tasks[0].RequiredResourceID = &resources[1].ID
+
+				resources := []*Resource{
+					NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+					resources[1],
+					NewResource("jmartin", "Juan Martín", "backend", parseDate("2021-06-07"), nil),
+				}
+
+				plan := NewProjectPlan("test-plan", tasks, resources, nil)
+				err := gplan.Planning(parseDate("2021-06-07"), plan)
+
+				Expect(err).Should(BeNil())
+				comparePlan(plan.Tasks, []string{
+					"2021-06-07 2021-06-14 cslopez",
+				})
+				Expect(plan.Tasks[0].ResourceIDs).Should(Equal([]gplan.ResourceID{"cslopez"}))
+			})
+		})
+
 	})
 
 	Describe("Review", func() {
@@ -568,8 +717,13 @@ func comparePlan(tasks []*Task, compare []string) {
 	var sTasks []string
 
 	for i := range tasks {
+		// Los hitos no tienen recurso
+		resourceID := gplan.ResourceID("-")
+		if tasks[i].ResourceID != nil {
+			resourceID = *tasks[i].ResourceID
+		}
 		s := fmt.Sprintf("%s %s %s", tasks[i].StartDate.Format("2006-01-02"), tasks[i].EndDate.Format("2006-01-02"),
-			*tasks[i].ResourceID)
+			resourceID)
 		sTasks = append(sTasks, s)
 		//log.Println(s)
 	}
@@ -0,0 +1,236 @@
+package gplan
+
+import (
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// TaskMove cambio de fechas de una tarea al redistribuir el trabajo de los recursos
+type TaskMove struct {
+	// ID de la tarea
+	TaskID TaskID
+	// Fechas de comienzo y fin que tenía la tarea
+	OriginalStartDate time.Time
+	OriginalEndDate   time.Time
+	// Fechas de comienzo y fin de la tarea después de moverla
+	StartDate time.Time
+	EndDate   time.Time
+	// Días laborables del plan que se ha retrasado el comienzo de la tarea
+	Delay uint
+}
+
+// LevelResources redistribuye el trabajo de los recursos de un plan que ya tiene fechas y recursos asignados, por
+// ejemplo porque se ha importado de otra herramienta, para que ningún recurso tenga dos tareas a la vez. Las tareas
+// se recorren en orden topológico y, entre las que están listas, por fecha de comienzo. Una tarea conserva sus fechas
+// si se cumplen sus dependencias y sus recursos están libres, si no se retrasa al primer hueco en el que estén libres
+// todos sus recursos y se cumplan sus dependencias, teniendo en cuenta sus calendarios y vacaciones. Las tareas
+// conservan sus recursos, salvo las divisibles que se mueven que pasan a hacerse de una vez con su primer recurso.
+// Devuelve la planificación resultante, que se puede copiar al plan con Apply, y las tareas que se han movido con el
+// retraso que han sufrido. No modifica el plan
+func LevelResources(plan ProjectPlan) (*Schedule, []TaskMove, *Error) {
+
+	var (
+		tasks          = plan.GetTasks()
+		tasksIndex     = make(map[TaskID]Task, len(tasks))
+		resourcesIndex = make(map[ResourceID]Resource)
+		taskIDs        = make([]TaskID, 0, len(tasks))
+		position       = make(map[TaskID]int, len(tasks))
+		startDate      time.Time
+		unknown        []TaskID
+	)
+
+	if len(tasks) == 0 {
+		return nil, nil, newTextError(ErrEmptyTaskList, "la lista de tareas a planificar está vacía")
+	}
+
+	for _, resource := range plan.GetResources() {
+		resourcesIndex[resource.GetID()] = resource
+	}
+
+	for i, task := range tasks {
+		if !isPlanned(task) {
+			return nil, nil, newTextError(ErrUnplannedTask, "Hay tareas sin planificar aun")
+		}
+		for _, resourceID := range taskResourceIDs(task) {
+			if _, exist := resourcesIndex[resourceID]; !exist {
+				unknown = append(unknown, task.GetID())
+				break
+			}
+		}
+
+		tasksIndex[task.GetID()] = task
+		taskIDs = append(taskIDs, task.GetID())
+		position[task.GetID()] = i
+
+		if i == 0 || dateutil.IsLt(task.GetStartDate().Local(), startDate) {
+			startDate = task.GetStartDate().Local()
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, nil, newError(ErrUnknownResource, "las siguientes tareas tienen asignado un recurso que no existe", unknown)
+	}
+
+	sortedIDs, ok := topologicalSort(taskIDs, taskSuccessors(tasks), func(a, b TaskID) bool {
+		dateA, dateB := tasksIndex[a].GetStartDate().Local(), tasksIndex[b].GetStartDate().Local()
+		if !dateutil.IsEqual(dateA, dateB) {
+			return dateutil.IsLt(dateA, dateB)
+		}
+		return position[a] < position[b]
+	})
+	if !ok {
+		return nil, nil, newTextError(ErrCircularDependency, "no se pueden redistribuir las tareas porque hay tareas con dependencias circulares")
+	}
+
+	// Las tareas se colocan en el primer hueco libre de la agenda de sus recursos
+	p := newPlanner(startDate, plan, tasksIndex)
+	p.schedulingMode = BackfillScheduling
+
+	var moves []TaskMove
+
+	for _, taskID := range sortedIDs {
+		task := tasksIndex[taskID]
+
+		assignment, err := p.levelTask(task, resourcesIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		originalStartDate, originalEndDate := task.GetStartDate().Local(), task.GetEndDate().Local()
+		if !dateutil.IsEqual(assignment.StartDate, originalStartDate) || !dateutil.IsEqual(assignment.EndDate, originalEndDate) {
+			moves = append(moves, TaskMove{
+				TaskID:            taskID,
+				OriginalStartDate: originalStartDate,
+				OriginalEndDate:   originalEndDate,
+				StartDate:         assignment.StartDate,
+				EndDate:           assignment.EndDate,
+				Delay:             p.calendar.laborableDays(originalStartDate.AddDate(0, 0, 1), assignment.StartDate),
+			})
+		}
+	}
+
+	return p.schedule(), moves, nil
+}
+
+// levelTask coloca una tarea ya planificada en la agenda de sus recursos, con sus fechas si puede o en el primer hueco
+// libre posterior si no
+func (p *planner) levelTask(task Task, resourcesIndex map[ResourceID]Resource) (*Assignment, *Error) {
+
+	var (
+		originalStartDate = task.GetStartDate().Local()
+		originalEndDate   = task.GetEndDate().Local()
+	)
+
+	startDate, minEndDate, err := p.getRealStartDate(task)
+	if err != nil {
+		return nil, err
+	}
+	if dateutil.IsLt(startDate, originalStartDate) {
+		startDate = originalStartDate
+	}
+
+	if IsMilestone(task) {
+		p.assignMilestone(task, startDate, minEndDate)
+		return p.assignments[task.GetID()], nil
+	}
+
+	var (
+		team     []teamMember
+		segments = task.GetSegments()
+	)
+	for _, resourceID := range taskResourceIDs(task) {
+		resource := resourcesIndex[resourceID]
+		team = append(team, teamMember{resource: resource, taskType: p.resourceProfiles[resourceID].demandedType(task)})
+	}
+
+	// Si puede conservar sus fechas se reservan tal cual, el trabajo de cada recurso en sus fechas y los tramos de una
+	// tarea divisible cada uno con su recurso
+	if p.fitsOriginalDates(task, team, startDate, minEndDate) {
+		info := &scheduledTaskInfo{
+			Resource:  team[0].resource,
+			StartDate: originalStartDate,
+			EndDate:   originalEndDate,
+			Segments:  segments,
+		}
+		efforts := taskEfforts(task)
+		for i, member := range team {
+			var (
+				memberStartDate = efforts[i].StartDate
+				memberEndDate   = efforts[i].EndDate
+				work            = uint(efforts[i].Days * 100)
+			)
+			// Si está dividida en tramos cada recurso trabaja desde su primer tramo hasta el último
+			if len(segments) > 0 {
+				work = 0
+				memberStartDate, memberEndDate = time.Time{}, time.Time{}
+				for _, segment := range segments {
+					if segment.ResourceID != member.resource.GetID() {
+						continue
+					}
+					if memberStartDate.IsZero() {
+						memberStartDate = segment.StartDate
+					}
+					memberEndDate = segment.EndDate
+					work += uint(segment.Days * 100)
+				}
+			}
+			info.Team = append(info.Team, member.resource)
+			info.StartDates = append(info.StartDates, memberStartDate)
+			info.EndDates = append(info.EndDates, memberEndDate)
+			info.Efforts = append(info.Efforts, work)
+		}
+		return p.book(task, info), nil
+	}
+
+	// Si hay que moverla se busca el primer hueco libre de sus recursos
+	var info *scheduledTaskInfo
+	switch {
+	case task.GetMaxTeamSize() > 1:
+		info = p.effortScheduledTask(task, team, startDate, minEndDate)
+	case len(segments) > 0:
+		info = p.scheduledTask(task, team[:1], startDate, minEndDate)
+	default:
+		info = p.scheduledTask(task, team, startDate, minEndDate)
+	}
+	if info.Incomplete {
+		return nil, newError(ErrUnschedulable, "las siguientes tareas no se pueden terminar antes de que sus recursos dejen de estar disponibles",
+			[]TaskID{task.GetID()})
+	}
+
+	return p.book(task, info), nil
+}
+
+// fitsOriginalDates devuelve True si una tarea puede conservar sus fechas: cumple sus dependencias, sus recursos están
+// disponibles y no tienen otra tarea en esas fechas
+func (p *planner) fitsOriginalDates(task Task, team []teamMember, startDate time.Time, minEndDate time.Time) bool {
+
+	var (
+		originalStartDate = task.GetStartDate().Local()
+		originalEndDate   = task.GetEndDate().Local()
+	)
+
+	if dateutil.IsGt(startDate, originalStartDate) || (!minEndDate.IsZero() && dateutil.IsLt(originalEndDate, minEndDate)) {
+		return false
+	}
+
+	if segments := task.GetSegments(); len(segments) > 0 {
+		for _, segment := range segments {
+			if p.timelines[segment.ResourceID].overlap(segment.StartDate, segment.EndDate) != nil ||
+				dateutil.IsLt(segment.StartDate, p.availableFrom[segment.ResourceID]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Cada recurso tiene que estar libre en las fechas en las que trabaja en la tarea
+	for _, effort := range taskEfforts(task) {
+		if p.timelines[effort.ResourceID].overlap(effort.StartDate, effort.EndDate) != nil ||
+			dateutil.IsLt(effort.StartDate, p.availableFrom[effort.ResourceID]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,110 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func compareMoves(moves []gplan.TaskMove) []string {
+	var result []string
+	for _, move := range moves {
+		result = append(result, fmt.Sprintf("%s %s %s %d", move.TaskID, move.StartDate.Format("2006-01-02"),
+			move.EndDate.Format("2006-01-02"), move.Delay))
+	}
+	return result
+}
+
+var _ = Describe("Level", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), []*Holidays{
+				NewHolidays(parseDate("2021-06-16"), parseDate("2021-06-16")),
+			}),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 3),
+			NewTask("Tarea2", "Summary", "backend", 20, 3),
+			NewTask("Tarea3", "Summary", "backend", 30, 3),
+			NewTask("QA", "Summary", "qa", 40, 2),
+		}
+
+		BlocksTo(tasks[2], tasks[3])
+
+		// El plan importado asigna a ahg tres tareas que se solapan
+		dates := [][2]string{
+			{"2021-06-07", "2021-06-09"},
+			{"2021-06-08", "2021-06-10"},
+			{"2021-06-09", "2021-06-11"},
+			{"2021-06-14", "2021-06-15"},
+		}
+		for i, task := range tasks {
+			task.StartDate = parseDate(dates[i][0])
+			task.EndDate = parseDate(dates[i][1])
+			task.ResourceID = &resources[0].ID
+		}
+		tasks[3].ResourceID = &resources[1].ID
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("un recurso tiene varias tareas a la vez", func() {
+
+		It("Debe mover las tareas al primer hueco libre respetando dependencias y vacaciones", func() {
+			schedule, moves, err := gplan.LevelResources(plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareMoves(moves)).Should(Equal([]string{
+				"Tarea2 2021-06-10 2021-06-14 2",
+				"Tarea3 2021-06-15 2021-06-18 4",
+				"QA 2021-06-21 2021-06-22 5",
+			}))
+
+			// No debe modificar el plan hasta que se aplique
+			Expect(plan.Tasks[1].StartDate).Should(Equal(parseDate("2021-06-08")))
+
+			schedule.Apply(plan)
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-09 ahg",
+				"2021-06-10 2021-06-14 ahg",
+				"2021-06-15 2021-06-18 ahg",
+				"2021-06-21 2021-06-22 Noemi",
+			})
+		})
+	})
+
+	When("ningún recurso tiene varias tareas a la vez", func() {
+
+		It("No debe mover ninguna tarea", func() {
+			plan.Tasks[1].StartDate = parseDate("2021-06-10")
+			plan.Tasks[1].EndDate = parseDate("2021-06-14")
+			plan.Tasks[2].StartDate = parseDate("2021-06-15")
+			plan.Tasks[2].EndDate = parseDate("2021-06-18")
+			plan.Tasks[3].StartDate = parseDate("2021-06-21")
+			plan.Tasks[3].EndDate = parseDate("2021-06-22")
+
+			_, moves, err := gplan.LevelResources(plan)
+			Expect(err).Should(BeNil())
+			Expect(moves).Should(BeEmpty())
+		})
+	})
+
+	When("hay tareas sin planificar", func() {
+
+		It("Debe devolver un error", func() {
+			plan.Tasks[0].ResourceID = nil
+
+			_, _, err := gplan.LevelResources(plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("Hay tareas sin planificar aun")))
+		})
+	})
+
+})
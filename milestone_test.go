@@ -0,0 +1,133 @@
+package gplan_test
+
+import (
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Milestone", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		tasks := []*Task{
+			NewTask("Desarrollo", "Summary", "backend", 10, 5),
+			NewTask("Release", "Release 1.0", "", 20, 0),
+			NewTask("QA", "Summary", "qa", 30, 2),
+			NewTask("Contrato", "Contrato firmado", "", 5, 0),
+		}
+
+		BlocksTo(tasks[0], tasks[1])
+		BlocksTo(tasks[1], tasks[2])
+
+		// El contrato se firma en una fecha fija
+		tasks[3].SetConstraint(gplan.MustStartOn, parseDate("2021-06-09"))
+
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("planificamos un plan con hitos", func() {
+
+		It("Los hitos deben tomar la fecha de sus dependencias o su fecha fija y no tener recurso", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-09 2021-06-09 -",
+				"2021-06-07 2021-06-11 ahg",
+				"2021-06-14 2021-06-14 -",
+				"2021-06-14 2021-06-15 Noemi",
+			})
+			Expect(plan.StartDate.Format("2006-01-02")).Should(Equal("2021-06-07"))
+			Expect(plan.EndDate.Format("2006-01-02")).Should(Equal("2021-06-15"))
+			Expect(plan.TotalTasks).Should(BeEquivalentTo(4))
+			Expect(plan.TotalDuration).Should(BeEquivalentTo(7))
+		})
+
+		It("Deben aparecer como hitos en la planificación", func() {
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			release, _ := schedule.GetAssignment("Release")
+			Expect(release.Milestone).Should(BeTrue())
+			Expect(release.ResourceID).Should(BeEmpty())
+			Expect(gplan.IsMilestone(plan.Tasks[1])).Should(BeTrue())
+		})
+
+		It("No deben contar en el avance ponderado por duración", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			plan.Tasks[0].RealProgress = 100
+			plan.Tasks[1].RealProgress = 100
+			plan.Tasks[2].RealProgress = 100
+
+			err = gplan.Review(plan, parseDate("2021-06-14"))
+			Expect(err).Should(BeNil())
+			Expect(plan.Tasks[0].ExpectedProgress).Should(BeEquivalentTo(100))
+			Expect(plan.Tasks[2].ExpectedProgress).Should(BeEquivalentTo(100))
+			Expect(plan.Tasks[3].ExpectedProgress).Should(BeEquivalentTo(0))
+			Expect(plan.ExpectedProgress).Should(BeEquivalentTo(71))
+			Expect(plan.RealProgress).Should(BeEquivalentTo(71))
+			Expect(plan.CompleteTasks).Should(BeEquivalentTo(3))
+		})
+
+		It("Deben estar en el camino crítico si no tienen holgura", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			info, cpErr := gplan.CriticalPath(plan)
+			Expect(cpErr).Should(BeNil())
+			Expect(info.CriticalPath).Should(Equal([]gplan.TaskID{"Desarrollo", "Release", "QA"}))
+			compareFloat(info.Tasks["Release"], "2021-06-14 2021-06-14 2021-06-14 2021-06-14 0 0")
+			compareFloat(info.Tasks["Contrato"], "2021-06-09 2021-06-09 2021-06-16 2021-06-16 5 5")
+		})
+	})
+
+	When("revisamos un plan que solo tiene hitos", func() {
+
+		BeforeEach(func() {
+			plan.Tasks = []*Task{plan.Tasks[1], plan.Tasks[3]}
+			plan.Tasks[0].BlocksBy = nil
+			plan.Tasks[0].BlocksTo = nil
+		})
+
+		It("Debe estar al 0% hasta que se alcancen todos los hitos", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+			Expect(plan.TotalDuration).Should(BeEquivalentTo(0))
+
+			plan.Tasks[0].RealProgress = 100
+
+			err = gplan.Review(plan, parseDate("2021-06-08"))
+			Expect(err).Should(BeNil())
+			Expect(plan.ExpectedProgress).Should(BeEquivalentTo(0))
+			Expect(plan.RealProgress).Should(BeEquivalentTo(0))
+			Expect(plan.RealProgressDays).Should(BeEquivalentTo(0))
+		})
+
+		It("Debe estar al 100% cuando se alcancen todos los hitos", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			plan.Tasks[0].RealProgress = 100
+			plan.Tasks[0].RealEndDate = parseDate("2021-06-07")
+			// El contrato se firma un día antes de lo previsto, que es el último día del plan
+			plan.Tasks[1].RealProgress = 100
+			plan.Tasks[1].RealEndDate = parseDate("2021-06-08")
+
+			err = gplan.Review(plan, parseDate("2021-06-10"))
+			Expect(err).Should(BeNil())
+			Expect(plan.ExpectedProgress).Should(BeEquivalentTo(100))
+			Expect(plan.RealProgress).Should(BeEquivalentTo(100))
+			Expect(plan.RealProgressDays).Should(BeEquivalentTo(0))
+		})
+	})
+
+})
@@ -10,6 +10,39 @@ type Holidays interface {
 	GetTo() time.Time
 }
 
+// Unavailability interface a implementar para los periodos en los que un recurso no está disponible por motivos
+// distintos de las vacaciones, como bajas o cesiones a otros proyectos
+type Unavailability interface {
+	Holidays
+	// Motivo por el que el recurso no está disponible
+	GetReason() string
+}
+
+// Calendar interface a implementar para definir un calendario laboral
+type Calendar interface {
+	// Días de la semana en los que se trabaja. Si no devuelve ninguno se trabaja de lunes a viernes
+	GetWorkingWeekdays() []time.Weekday
+	// Rangos de días no laborables
+	GetNonWorkingDays() []Holidays
+}
+
+// Allocation interface a implementar para definir el porcentaje de dedicación de un recurso a partir de una fecha
+type Allocation interface {
+	// Fecha desde la que se aplica el porcentaje de dedicación
+	GetFrom() time.Time
+	// Porcentaje de la jornada que el recurso dedica a la planificación, entre 1 y 100
+	GetPercentage() uint
+}
+
+// Skill interface a implementar para definir un tipo de tarea que puede realizar un recurso
+type Skill interface {
+	// Tipo de tarea que puede realizar el recurso
+	GetType() string
+	// Rendimiento del recurso en este tipo de tareas en porcentaje, 100 es el rendimiento normal. Si es 0 se usa 100.
+	// Se aplica además del rendimiento general del recurso
+	GetEfficiency() uint
+}
+
 // ResourceID alias del ID único de un recurso
 type ResourceID string
 
@@ -19,24 +52,83 @@ type Resource interface {
 	GetID() ResourceID
 	// Descripción del recurso
 	GetDescription() string
-	// Clasifica el tipo de recurso, Deberá coincidir con el tipo de tarea a asignarle.
+	// Clasifica el tipo de recurso, Deberá coincidir con el tipo de tarea a asignarle salvo que tenga otras habilidades.
 	GetType() string
+	// Otros tipos de tarea que puede realizar el recurso además de las de su tipo. Si incluye su tipo se usa el
+	// rendimiento indicado para él
+	GetSkills() []Skill
+	// Rendimiento general del recurso en porcentaje, 100 es el rendimiento normal y 200 hace una jornada estándar en
+	// media jornada. Si es 0 se usa 100
+	GetEfficiency() uint
 	// Fecha desde la que estará disponible para poder asignarle una tarea
 	GetAvailableFrom() time.Time
 	SetAvailableFrom(time.Time)
+	// Último día en el que estará disponible, si está vacía estará disponible indefinidamente
+	GetAvailableUntil() time.Time
 	// Siguiente fecha de disponibilidad. De uso interno para calcular la siguiente fecha de disponibilidad
 	GetNextAvailableDate() time.Time
 	SetNextAvailableDate(time.Time)
 	// Vacaciones del recurso
 	GetHolidays() []Holidays
+	// Periodos en los que el recurso no está disponible
+	GetUnavailabilities() []Unavailability
+	// Calendario laboral del recurso, si es nil se usa el del plan. Los festivos del plan se aplican siempre
+	GetCalendar() Calendar
+	// Porcentajes de dedicación del recurso, cada uno se aplica desde su fecha hasta la del siguiente. Antes del primero
+	// o si no tiene ninguno el recurso se dedica al 100%
+	GetAllocations() []Allocation
+	// Coste de una jornada de trabajo del recurso, lo usa la estrategia de asignación CheapestStrategy
+	GetCost() float64
 }
 
+// DependencyType tipo de dependencia entre dos tareas
+type DependencyType uint
+
+const (
+	// FinishToStart la tarea bloqueada no puede comenzar hasta que termine la que la bloquea
+	FinishToStart DependencyType = iota
+	// StartToStart la tarea bloqueada no puede comenzar hasta que comience la que la bloquea
+	StartToStart
+	// FinishToFinish la tarea bloqueada no puede terminar hasta que termine la que la bloquea
+	FinishToFinish
+	// StartToFinish la tarea bloqueada no puede terminar hasta que comience la que la bloquea
+	StartToFinish
+)
+
 // TaskDependency interface a implementar para definir una dependencia entre tareas
 type TaskDependency interface {
 	// ID de la tarea de la que depende
 	GetTaskID() TaskID
+	// Tipo de dependencia, el valor por defecto es FinishToStart
+	GetType() DependencyType
+	// Desfase en días laborables que se aplica a la dependencia, si es negativo adelanta la tarea bloqueada
+	GetLag() int
+}
+
+// ResourceDemand interface a implementar para definir cuántos recursos de un tipo necesita una tarea a la vez
+type ResourceDemand interface {
+	// Tipo de recurso necesario
+	GetResourceType() string
+	// Número de recursos de ese tipo que deben trabajar a la vez en la tarea, como mínimo 1
+	GetQuantity() uint
 }
 
+// ConstraintType tipo de restricción de fecha de una tarea
+type ConstraintType uint
+
+const (
+	// NoConstraint la tarea no tiene restricción de fecha
+	NoConstraint ConstraintType = iota
+	// StartNoEarlierThan la tarea no puede comenzar antes de la fecha
+	StartNoEarlierThan
+	// StartNoLaterThan la tarea tiene que comenzar como muy tarde en la fecha
+	StartNoLaterThan
+	// FinishNoLaterThan la tarea tiene que terminar como muy tarde en la fecha
+	FinishNoLaterThan
+	// MustStartOn la tarea tiene que comenzar exactamente en la fecha
+	MustStartOn
+)
+
 // TaskID Alias para los ID de las tareas
 type TaskID string
 
@@ -48,6 +140,22 @@ type Task interface {
 	GetSummary() string
 	// Tipo de recurso que podrá ser asignado a esta tarea
 	GetResourceType() string
+	// Recursos que necesita la tarea trabajando a la vez. Si no tiene ninguna demanda necesita un recurso del tipo
+	// GetResourceType()
+	GetResourceDemands() []ResourceDemand
+	// Número máximo de recursos de su tipo entre los que se puede repartir la duración de la tarea. Si es mayor que 1
+	// la duración es el esfuerzo total de la tarea y la planificación elige cuántos recursos asignarle para que
+	// termine antes
+	GetMaxTeamSize() uint
+	// Recurso que tiene que realizar la tarea, si es nil la puede realizar cualquier recurso de su tipo
+	GetRequiredResourceID() *ResourceID
+	// Recursos que se prefieren para la tarea, si alguno de ellos puede realizarla se elige entre ellos
+	GetPreferredResourceIDs() []ResourceID
+	// Recursos que no pueden realizar la tarea
+	GetExcludedResourceIDs() []ResourceID
+	// Indica si la tarea se puede interrumpir y dividir en tramos, para dejar pasar antes a tareas ya planificadas o
+	// para que otro recurso termine lo que queda cuando el suyo tiene una ausencia larga
+	IsSplittable() bool
 	// Número de orden de la tarea dentro de la lista de tareas
 	GetOrder() uint
 	// Duración de la tarea en días
@@ -73,15 +181,39 @@ type Task interface {
 	// Duración completada esperada según lo planificado
 	GetExpectedCompleteDuration() uint
 	SetExpectedCompleteDuration(uint)
-	// Recurso asignado
+	// Recurso asignado, si la tarea necesita varios recursos es el primero de ellos
 	GetResourceID() *ResourceID
 	SetResourceID(*ResourceID)
+	// Todos los recursos asignados
+	GetResourceIDs() []ResourceID
+	SetResourceIDs([]ResourceID)
+	// Tramos planificados de una tarea divisible
+	GetSegments() []Segment
+	SetSegments([]Segment)
+	// Trabajo planificado de cada recurso asignado, con las fechas en las que trabaja cada uno en la tarea
+	GetEfforts() []Effort
+	SetEfforts([]Effort)
 	// IDs de las tareas a las que bloquea esta tarea
 	GetBlocksTo() []TaskDependency
 	// IDs de las tareas que bloquean a esta tarea
 	GetBlocksBy() []TaskDependency
+	// Tipo de restricción de fecha de la tarea
+	GetConstraintType() ConstraintType
+	// Fecha de la restricción de fecha de la tarea
+	GetConstraintDate() time.Time
 }
 
+// SchedulingMode modo en el que se buscan las fechas de una tarea en la agenda de un recurso
+type SchedulingMode uint
+
+const (
+	// AppendScheduling las tareas se planifican siempre a continuación de la última tarea asignada al recurso
+	AppendScheduling SchedulingMode = iota
+	// BackfillScheduling las tareas se planifican en el primer hueco libre del recurso en el que quepan, aunque sea
+	// anterior a otras tareas ya asignadas
+	BackfillScheduling
+)
+
 // ProjectPlanID alias para el identificador de un plan de proyecto
 type ProjectPlanID string
 
@@ -132,6 +264,18 @@ type ProjectPlan interface {
 	GetResources() []Resource
 	// Festivos
 	GetFeastDays() []Holidays
+	// Calendario laboral del plan, si es nil se trabaja de lunes a viernes. Los festivos se aplican además del calendario
+	GetCalendar() Calendar
+	// Modo en el que se buscan las fechas de las tareas en la agenda de los recursos, por defecto AppendScheduling
+	GetSchedulingMode() SchedulingMode
+	// Estrategia para elegir el recurso al que se asigna cada tarea, si es nil se usa EarliestFinishStrategy
+	GetAssignmentStrategy() AssignmentStrategy
+	// Días laborables seguidos del plan en los que un recurso no trabaja a partir de los cuales se interrumpe una tarea
+	// divisible, si es 0 se usan 5
+	GetMinSplitGap() uint
+	// Regla de prioridad con la que se elige qué tarea se planifica primero de entre las que están listas para
+	// planificarse, por defecto ManualOrderRule
+	GetPriorityRule() PriorityRule
 	// Método que ordena las tareas por el campo Orden
 	SortTasksByOrder()
 	// Fecha de revisión del plan
@@ -141,16 +285,32 @@ type ProjectPlan interface {
 
 // scheduledTaskInfo datos de la planificación de una tarea
 type scheduledTaskInfo struct {
-	// Recurso asignado
+	// Recurso asignado, si la tarea necesita varios recursos es el primero de ellos
 	Resource Resource
+	// Todos los recursos asignados
+	Team []Resource
+	// Indica que la tarea no se puede terminar antes de que alguno de sus recursos deje de estar disponible
+	Incomplete bool
+	// Fechas en las que empieza y termina de trabajar en la tarea cada recurso del equipo
+	StartDates []time.Time
+	EndDates   []time.Time
+	// Trabajo de cada recurso del equipo en porcentaje de jornadas
+	Efforts []uint
 	// Fecha planificada de comienzo de la tarea
 	StartDate time.Time
 	// Fecha planificada de fin de la tarea
 	EndDate time.Time
+	// Tramos de una tarea divisible
+	Segments []Segment
 }
 
-// Error Contiene información de un Message que se haya podido producir al crear o revisar la planificación
+// Error Contiene información de un Message que se haya podido producir al crear o revisar la planificación. Implementa
+// error y su tipo, uno de los errores Err*, se puede comprobar con errors.Is
 type Error struct {
 	Message error
 	Tasks   []TaskID
+	// Recursos afectados
+	Resources []ResourceID
+	// Tipo del error
+	Kind error
 }
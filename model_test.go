@@ -33,6 +33,123 @@ func (s *Holidays) GetTo() time.Time {
 	return s.To
 }
 
+//---- Unavailability
+
+// Unavailability contiene un periodo en el que un recurso no está disponible
+type Unavailability struct {
+	// Fecha desde
+	From time.Time `json:"from"`
+	// Fecha hasta
+	To time.Time `json:"to"`
+	// Motivo
+	Reason string `json:"reason"`
+}
+
+// NewUnavailability crea un nuevo periodo sin disponibilidad
+func NewUnavailability(from time.Time, to time.Time, reason string) *Unavailability {
+	return &Unavailability{
+		From:   from,
+		To:     to,
+		Reason: reason,
+	}
+}
+
+func (s *Unavailability) GetFrom() time.Time {
+	return s.From
+}
+
+func (s *Unavailability) GetTo() time.Time {
+	return s.To
+}
+
+func (s *Unavailability) GetReason() string {
+	return s.Reason
+}
+
+//---- Calendar
+
+// Calendar contiene los días laborables de la semana y los días no laborables de un calendario
+type Calendar struct {
+	// Días de la semana en los que se trabaja
+	WorkingWeekdays []time.Weekday `json:"workingWeekdays"`
+	// Rangos de días no laborables
+	NonWorkingDays []*Holidays `json:"nonWorkingDays"`
+}
+
+// NewCalendar crea un nuevo calendario
+func NewCalendar(workingWeekdays []time.Weekday, nonWorkingDays []*Holidays) *Calendar {
+	return &Calendar{
+		WorkingWeekdays: workingWeekdays,
+		NonWorkingDays:  nonWorkingDays,
+	}
+}
+
+func (s *Calendar) GetWorkingWeekdays() []time.Weekday {
+	return s.WorkingWeekdays
+}
+
+func (s *Calendar) GetNonWorkingDays() []gplan.Holidays {
+	var slice = []gplan.Holidays{}
+
+	for i := range s.NonWorkingDays {
+		slice = append(slice, s.NonWorkingDays[i])
+	}
+
+	return slice
+}
+
+//---- Allocation
+
+// Allocation contiene el porcentaje de dedicación de un recurso a partir de una fecha
+type Allocation struct {
+	// Fecha desde la que se aplica
+	From time.Time `json:"from"`
+	// Porcentaje de dedicación
+	Percentage uint `json:"percentage"`
+}
+
+// NewAllocation crea un nuevo porcentaje de dedicación
+func NewAllocation(from time.Time, percentage uint) *Allocation {
+	return &Allocation{
+		From:       from,
+		Percentage: percentage,
+	}
+}
+
+func (s *Allocation) GetFrom() time.Time {
+	return s.From
+}
+
+func (s *Allocation) GetPercentage() uint {
+	return s.Percentage
+}
+
+//---- Skill
+
+// Skill contiene un tipo de tarea que puede realizar un recurso y su rendimiento en ellas
+type Skill struct {
+	// Tipo de tarea
+	Type string `json:"type"`
+	// Rendimiento en porcentaje
+	Efficiency uint `json:"efficiency"`
+}
+
+// NewSkill crea una nueva habilidad
+func NewSkill(skillType string, efficiency uint) *Skill {
+	return &Skill{
+		Type:       skillType,
+		Efficiency: efficiency,
+	}
+}
+
+func (s *Skill) GetType() string {
+	return s.Type
+}
+
+func (s *Skill) GetEfficiency() uint {
+	return s.Efficiency
+}
+
 //---- Resource
 
 // Resource Contiene información de un recurso
@@ -49,6 +166,20 @@ type Resource struct {
 	nextAvailableDate time.Time
 	// Días de vacaciones del recurso
 	Holidays []*Holidays
+	// Calendario laboral del recurso
+	Calendar *Calendar
+	// Porcentajes de dedicación
+	Allocations []*Allocation
+	// Coste por jornada
+	Cost float64
+	// Otros tipos de tarea que puede realizar
+	Skills []*Skill
+	// Rendimiento general en porcentaje
+	Efficiency uint
+	// Último día en el que estará disponible
+	AvailableUntil time.Time `json:"availableUntil"`
+	// Periodos sin disponibilidad
+	Unavailabilities []*Unavailability
 }
 
 // NewResource crea un nuevo recurso
@@ -106,11 +237,64 @@ func (s *Resource) GetHolidays() []gplan.Holidays {
 	return slice
 }
 
+func (s *Resource) GetCalendar() gplan.Calendar {
+	if s.Calendar == nil {
+		return nil
+	}
+	return s.Calendar
+}
+
+func (s *Resource) GetAllocations() []gplan.Allocation {
+	var slice = []gplan.Allocation{}
+
+	for i := range s.Allocations {
+		slice = append(slice, s.Allocations[i])
+	}
+
+	return slice
+}
+
+func (s *Resource) GetSkills() []gplan.Skill {
+	var slice = []gplan.Skill{}
+
+	for i := range s.Skills {
+		slice = append(slice, s.Skills[i])
+	}
+
+	return slice
+}
+
+func (s *Resource) GetAvailableUntil() time.Time {
+	return s.AvailableUntil
+}
+
+func (s *Resource) GetUnavailabilities() []gplan.Unavailability {
+	var slice = []gplan.Unavailability{}
+
+	for i := range s.Unavailabilities {
+		slice = append(slice, s.Unavailabilities[i])
+	}
+
+	return slice
+}
+
+func (s *Resource) GetEfficiency() uint {
+	return s.Efficiency
+}
+
+func (s *Resource) GetCost() float64 {
+	return s.Cost
+}
+
 //---- TaskDependency
 
 // TaskDependency contiene información de una dependencia de una tarea
 type TaskDependency struct {
 	TaskID gplan.TaskID `json:"taskId"`
+	// Tipo de dependencia
+	Type gplan.DependencyType `json:"type"`
+	// Desfase en días laborables
+	Lag int `json:"lag"`
 }
 
 // GetTaskID implementa TaskDependency
@@ -118,11 +302,52 @@ func (s *TaskDependency) GetTaskID() gplan.TaskID {
 	return s.TaskID
 }
 
+// GetType implementa TaskDependency
+func (s *TaskDependency) GetType() gplan.DependencyType {
+	return s.Type
+}
+
+// GetLag implementa TaskDependency
+func (s *TaskDependency) GetLag() int {
+	return s.Lag
+}
+
 // NewTaskDependency Crea un nuevo objeto TaskDependency
 func NewTaskDependency(taskID gplan.TaskID) *TaskDependency {
 	return &TaskDependency{TaskID: taskID}
 }
 
+// NewTaskDependencyWithType Crea un nuevo objeto TaskDependency con un tipo y un desfase
+func NewTaskDependencyWithType(taskID gplan.TaskID, depType gplan.DependencyType, lag int) *TaskDependency {
+	return &TaskDependency{TaskID: taskID, Type: depType, Lag: lag}
+}
+
+//---- ResourceDemand
+
+// ResourceDemand contiene el número de recursos de un tipo que necesita una tarea a la vez
+type ResourceDemand struct {
+	// Tipo de recurso
+	ResourceType string `json:"resourceType"`
+	// Número de recursos
+	Quantity uint `json:"quantity"`
+}
+
+// NewResourceDemand crea una nueva demanda de recursos
+func NewResourceDemand(resourceType string, quantity uint) *ResourceDemand {
+	return &ResourceDemand{
+		ResourceType: resourceType,
+		Quantity:     quantity,
+	}
+}
+
+func (s *ResourceDemand) GetResourceType() string {
+	return s.ResourceType
+}
+
+func (s *ResourceDemand) GetQuantity() uint {
+	return s.Quantity
+}
+
 //--- Task
 
 // Task Contiene información de una tarea
@@ -153,10 +378,32 @@ type Task struct {
 	RealEndDate time.Time `json:"realEndDate"`
 	// Recurso asignado
 	ResourceID *gplan.ResourceID
+	// Recursos que necesita a la vez
+	ResourceDemands []*ResourceDemand
+	// Todos los recursos asignados
+	ResourceIDs []gplan.ResourceID
+	// Número máximo de recursos entre los que repartir la duración
+	MaxTeamSize uint `json:"maxTeamSize"`
+	// Recurso que tiene que realizarla
+	RequiredResourceID *gplan.ResourceID
+	// Recursos preferidos
+	PreferredResourceIDs []gplan.ResourceID
+	// Recursos excluidos
+	ExcludedResourceIDs []gplan.ResourceID
+	// Indica si se puede dividir en tramos
+	Splittable bool `json:"splittable"`
+	// Tramos planificados
+	Segments []gplan.Segment
+	// Trabajo planificado de cada recurso
+	Efforts []gplan.Effort
 	// IDs de las tareas a las que bloquea esta tarea
 	BlocksTo []*TaskDependency
 	// IDs de las tareas que bloquean a esta tarea
 	BlocksBy []*TaskDependency
+	// Tipo de restricción de fecha
+	ConstraintType gplan.ConstraintType `json:"constraintType"`
+	// Fecha de la restricción de fecha
+	ConstraintDate time.Time `json:"constraintDate"`
 }
 
 // NewTask crea una nueva tarea
@@ -202,6 +449,16 @@ func BlocksTo(taskBlocks *Task, taskBloked *Task) {
 	}
 }
 
+// BlocksToWithType Crea una referencia de una tarea que bloquea a otra tarea con un tipo de dependencia y un desfase
+func BlocksToWithType(taskBlocks *Task, taskBloked *Task, depType gplan.DependencyType, lag int) {
+	if !hasTaskDependency(taskBloked.ID, taskBlocks.BlocksTo) {
+		taskBlocks.BlocksTo = append(taskBlocks.BlocksTo, NewTaskDependencyWithType(taskBloked.ID, depType, lag))
+	}
+	if !hasTaskDependency(taskBlocks.ID, taskBloked.BlocksBy) {
+		taskBloked.BlocksBy = append(taskBloked.BlocksBy, NewTaskDependencyWithType(taskBlocks.ID, depType, lag))
+	}
+}
+
 // taskIDExists Devuelve true si el ID de una tarea existe en el arrays de IDs
 func hasTaskDependency(taskID gplan.TaskID, taskDependencies []*TaskDependency) bool {
 	for _, td := range taskDependencies {
@@ -296,6 +553,60 @@ func (s *Task) SetResourceID(r *gplan.ResourceID) {
 	s.ResourceID = r
 }
 
+func (s *Task) GetResourceDemands() []gplan.ResourceDemand {
+	var slice = []gplan.ResourceDemand{}
+
+	for i := range s.ResourceDemands {
+		slice = append(slice, s.ResourceDemands[i])
+	}
+
+	return slice
+}
+
+func (s *Task) GetMaxTeamSize() uint {
+	return s.MaxTeamSize
+}
+
+func (s *Task) GetRequiredResourceID() *gplan.ResourceID {
+	return s.RequiredResourceID
+}
+
+func (s *Task) GetPreferredResourceIDs() []gplan.ResourceID {
+	return s.PreferredResourceIDs
+}
+
+func (s *Task) GetExcludedResourceIDs() []gplan.ResourceID {
+	return s.ExcludedResourceIDs
+}
+
+func (s *Task) GetResourceIDs() []gplan.ResourceID {
+	return s.ResourceIDs
+}
+
+func (s *Task) SetResourceIDs(ids []gplan.ResourceID) {
+	s.ResourceIDs = ids
+}
+
+func (s *Task) IsSplittable() bool {
+	return s.Splittable
+}
+
+func (s *Task) GetSegments() []gplan.Segment {
+	return s.Segments
+}
+
+func (s *Task) SetSegments(segments []gplan.Segment) {
+	s.Segments = segments
+}
+
+func (s *Task) GetEfforts() []gplan.Effort {
+	return s.Efforts
+}
+
+func (s *Task) SetEfforts(efforts []gplan.Effort) {
+	s.Efforts = efforts
+}
+
 func (s *Task) GetBlocksTo() []gplan.TaskDependency {
 	var slice = []gplan.TaskDependency{}
 
@@ -316,6 +627,20 @@ func (s *Task) GetBlocksBy() []gplan.TaskDependency {
 	return slice
 }
 
+func (s *Task) GetConstraintType() gplan.ConstraintType {
+	return s.ConstraintType
+}
+
+func (s *Task) GetConstraintDate() time.Time {
+	return s.ConstraintDate
+}
+
+// SetConstraint pone una restricción de fecha a la tarea
+func (s *Task) SetConstraint(constraintType gplan.ConstraintType, date time.Time) {
+	s.ConstraintType = constraintType
+	s.ConstraintDate = date
+}
+
 //---- ProjectPlan
 
 // ProjectPlan Contiene información de la planificación del proyecto
@@ -354,6 +679,16 @@ type ProjectPlan struct {
 	FeastDays []*Holidays
 	// Fecha de revisión
 	ReviewDate time.Time
+	// Calendario laboral
+	Calendar *Calendar
+	// Modo de búsqueda de huecos en la agenda de los recursos
+	SchedulingMode gplan.SchedulingMode
+	// Estrategia de asignación de recursos
+	AssignmentStrategy gplan.AssignmentStrategy
+	// Días sin trabajar a partir de los cuales se divide una tarea
+	MinSplitGap uint
+	// Regla de prioridad para ordenar las tareas
+	PriorityRule gplan.PriorityRule
 }
 
 // NewProjectPlan crea un nuevo plan de proyecto para poder ser planificado o revisado
@@ -504,6 +839,29 @@ func (s *ProjectPlan) GetFeastDays() []gplan.Holidays {
 	return slice
 }
 
+func (s *ProjectPlan) GetCalendar() gplan.Calendar {
+	if s.Calendar == nil {
+		return nil
+	}
+	return s.Calendar
+}
+
+func (s *ProjectPlan) GetSchedulingMode() gplan.SchedulingMode {
+	return s.SchedulingMode
+}
+
+func (s *ProjectPlan) GetAssignmentStrategy() gplan.AssignmentStrategy {
+	return s.AssignmentStrategy
+}
+
+func (s *ProjectPlan) GetMinSplitGap() uint {
+	return s.MinSplitGap
+}
+
+func (s *ProjectPlan) GetPriorityRule() gplan.PriorityRule {
+	return s.PriorityRule
+}
+
 // SortTasksByOrder Implementa SortTasksByOrder
 func (s *ProjectPlan) SortTasksByOrder() {
 	// Ordena las tareas por número de orden para poder planificarlas
@@ -9,387 +9,986 @@ import (
 )
 
 // Planning Crea una nueva planificación a partir de unas tareas, recursos, vacaciones y fecha de comienzo.
+// Guarda el resultado en las tareas, los recursos y el plan, si se quiere obtener la planificación sin modificarlos
+// se debe usar PlanSchedule.
 func Planning(startDate time.Time, plan ProjectPlan) *Error {
 
+	// Ordena las tareas del plan por número de orden como se ha hecho siempre
+	plan.SortTasksByOrder()
+
+	schedule, err := PlanSchedule(startDate, plan)
+	if err != nil {
+		return err
+	}
+
+	schedule.Apply(plan)
+
+	log.Printf("StartDate %s\n", plan.GetStartDate())
+	log.Printf("EndDate %s\n", plan.GetEndDate())
+
+	for _, task := range plan.GetTasks() {
+		log.Printf("%s %s %d %s %s\n", task.GetID(), task.GetSummary(), task.GetDuration(), task.GetStartDate(), task.GetEndDate())
+	}
+
+	return nil
+}
+
+// PlanSchedule Calcula una planificación a partir de las tareas, recursos, vacaciones y fecha de comienzo del plan sin
+// modificar ninguno de ellos. El resultado se puede copiar al plan con Schedule.Apply.
+// La fecha de comienzo que ya tenga una tarea no se tiene en cuenta, para que una tarea no comience antes de una fecha
+// se le debe poner la restricción StartNoEarlierThan
+func PlanSchedule(startDate time.Time, plan ProjectPlan) (*Schedule, *Error) {
+
 	// Convierte a local startDate
 	startDate = startDate.Local()
 
-	// Hay que ordenarlas antes de que asigne las tareas ya que las extrae a otro array distinto
-	plan.SortTasksByOrder()
-
 	var (
-		tasks      = plan.GetTasks()
-		resources  = plan.GetResources()
-		feastDays  = plan.GetFeastDays()
-		tasksIndex map[TaskID]Task
-		err        *Error
+		tasks     = plan.GetTasks()
+		resources = plan.GetResources()
 	)
 
-	tasksIndex, err = validateTasks(tasks, resources)
+	// Ordena por número de orden una copia de la lista de tareas para no modificar el plan
+	tasks = append([]Task{}, tasks...)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].GetOrder() < tasks[j].GetOrder()
+	})
+
+	tasksIndex, warnings, err := validateTasks(tasks, resources)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Si la fecha de disponibilidad del recurso es menor que la fecha en la que debe comenzar el proyecto se le pone la fecha en la que debe comenzar el proyecto
-	// para que no haya ninguna tarea que comience antes
-	for _, resource := range resources {
-
-		// Convierte a local nextAvailabledate
-		resource.SetNextAvailableDate(resource.GetNextAvailableDate().Local())
-
-		if dateutil.IsLt(resource.GetNextAvailableDate(), startDate) {
-			resource.SetNextAvailableDate(startDate)
-		}
+	if plan.GetPriorityRule() > EarliestConstraintRule {
+		return nil, newTextError(ErrInvalidPlan, "la regla de prioridad del plan es desconocida")
 	}
 
-	if feastDays == nil {
-		feastDays = []Holidays{}
+	// Las tareas se planifican en orden topológico, de manera que una tarea siempre se planifica después de las tareas
+	// que la bloquean. La regla de prioridad del plan y después el número de orden solo deciden entre tareas que están
+	// listas para planificarse
+	tasks, err = sortTasksByDependencies(tasks, tasksIndex, plan.GetPriorityRule())
+	if err != nil {
+		return nil, err
 	}
 
+	p := newPlanner(startDate, plan, tasksIndex)
+	p.warnings = append(p.warnings, warnings...)
+
 	// Planifica las tareas
 	for _, task := range tasks {
 
-		err = assignTask(task, resources, feastDays, tasksIndex)
+		err = p.assignTask(task)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	plan.SetStartDate(tasks[0].GetStartDate())
-	plan.SetEndDate(tasks[0].GetEndDate())
+	if len(p.unmetConstraints) > 0 {
+		return nil, newError(ErrUnmetConstraint, "no se pueden cumplir las restricciones de fecha de las siguientes tareas", p.unmetConstraints)
+	}
 
-	log.Printf("StartDate %s\n", plan.GetStartDate())
-	log.Printf("EndDate %s\n", plan.GetEndDate())
+	return p.schedule(), nil
+}
+
+// sortTasksByDependencies devuelve las tareas en orden topológico según sus dependencias. Entre las tareas que no
+// tienen bloqueos pendientes elige primero la de más prioridad según la regla de prioridad, después la de menor número
+// de orden y, a igualdad de orden, la que aparece antes. Si hay dependencias circulares devuelve un error
+func sortTasksByDependencies(tasks []Task, tasksIndex map[TaskID]Task, rule PriorityRule) ([]Task, *Error) {
 
-	var totalDuration uint
+	var (
+		taskIDs    = make([]TaskID, 0, len(tasks))
+		position   = make(map[TaskID]int, len(tasks))
+		successors = taskSuccessors(tasks)
+	)
+
+	for i, task := range tasks {
+		taskIDs = append(taskIDs, task.GetID())
+		position[task.GetID()] = i
+	}
+
+	priority, err := priorityComparator(rule, tasksIndex, successors)
+	if err != nil {
+		return nil, err
+	}
+
+	sortedIDs, ok := topologicalSort(taskIDs, successors, func(a, b TaskID) bool {
+		if c := priority(a, b); c != 0 {
+			return c < 0
+		}
+		if tasksIndex[a].GetOrder() != tasksIndex[b].GetOrder() {
+			return tasksIndex[a].GetOrder() < tasksIndex[b].GetOrder()
+		}
+		return position[a] < position[b]
+	})
+	if !ok {
+		return nil, newTextError(ErrCircularDependency, "no se pueden ordenar las tareas porque hay tareas con dependencias circulares")
+	}
+
+	sorted := make([]Task, 0, len(sortedIDs))
+	for _, taskID := range sortedIDs {
+		sorted = append(sorted, tasksIndex[taskID])
+	}
+
+	return sorted, nil
+}
+
+// planner contiene el estado de una planificación en curso. Las tareas y recursos del plan solo se leen, los datos
+// calculados se guardan en el propio planner
+type planner struct {
+	// Fecha de comienzo del plan
+	startDate time.Time
+	// Recursos disponibles
+	resources []Resource
+	// Calendario del plan, con el que se cuentan los desfases de las dependencias y las jornadas del plan
+	calendar *workCalendar
+	// Perfil de trabajo de cada recurso: su calendario, que incluye sus vacaciones y los festivos del plan, y su dedicación
+	resourceProfiles map[ResourceID]*resourceProfile
+	// Índice de tareas por ID
+	tasksIndex map[TaskID]Task
+	// Modo de búsqueda de fechas en la agenda de los recursos
+	schedulingMode SchedulingMode
+	// Fecha desde la que está disponible cada recurso
+	availableFrom map[ResourceID]time.Time
+	// Siguiente fecha de disponibilidad de cada recurso
+	nextAvailableDates map[ResourceID]time.Time
+	// Agenda de cada recurso
+	timelines map[ResourceID]resourceTimeline
+	// Estrategia con la que se elige el recurso de cada tarea
+	strategy AssignmentStrategy
+	// Jornadas asignadas a cada recurso
+	assignedWorkdays map[ResourceID]uint
+	// Días laborables seguidos sin trabajar a partir de los cuales se interrumpe una tarea divisible
+	minSplitGap uint
+	// Planificación de las tareas ya asignadas
+	assignments map[TaskID]*Assignment
+	// IDs de las tareas en el orden en el que se han planificado
+	scheduled []TaskID
+	// Avisos que no impiden realizar la planificación
+	warnings []*Error
+	// IDs de las tareas cuya restricción de fecha no se puede cumplir
+	unmetConstraints []TaskID
+	// Dependencias de cada tarea con las tareas que la bloquean, también las declaradas solo en la tarea que bloquea
+	blocksBy map[TaskID][]TaskDependency
+	// Dependencias de cada tarea con las tareas a las que bloquea, también las declaradas solo en la tarea bloqueada
+	blocksTo map[TaskID][]TaskDependency
+}
+
+// newPlanner crea el planner para planificar un plan a partir de una fecha de comienzo
+func newPlanner(startDate time.Time, plan ProjectPlan, tasksIndex map[TaskID]Task) *planner {
+
+	p := &planner{
+		startDate:          startDate,
+		resources:          plan.GetResources(),
+		calendar:           newPlanCalendar(plan),
+		resourceProfiles:   make(map[ResourceID]*resourceProfile),
+		tasksIndex:         tasksIndex,
+		schedulingMode:     plan.GetSchedulingMode(),
+		availableFrom:      make(map[ResourceID]time.Time),
+		nextAvailableDates: make(map[ResourceID]time.Time),
+		timelines:          make(map[ResourceID]resourceTimeline),
+		strategy:           plan.GetAssignmentStrategy(),
+		assignedWorkdays:   make(map[ResourceID]uint),
+		minSplitGap:        plan.GetMinSplitGap(),
+		assignments:        make(map[TaskID]*Assignment, len(tasksIndex)),
+	}
+
+	if p.strategy == nil {
+		p.strategy = EarliestFinishStrategy{}
+	}
+
+	if p.minSplitGap == 0 {
+		p.minSplitGap = defaultMinSplitGap
+	}
+
+	// Una dependencia puede estar declarada solo en una de las dos tareas, se tiene en cuenta desde las dos
+	p.blocksBy, p.blocksTo = taskDependencies(plan.GetTasks())
+
+	// Si la fecha de disponibilidad del recurso es menor que la fecha en la que debe comenzar el proyecto se le pone la
+	// fecha en la que debe comenzar el proyecto para que no haya ninguna tarea que comience antes
+	for _, resource := range p.resources {
+		availableFrom := resource.GetAvailableFrom().Local()
+		if dateutil.IsLt(availableFrom, startDate) {
+			availableFrom = startDate
+		}
+		p.availableFrom[resource.GetID()] = availableFrom
+		p.nextAvailableDates[resource.GetID()] = availableFrom
+		p.resourceProfiles[resource.GetID()] = newResourceProfile(plan, resource)
+	}
+
+	return p
+}
+
+// schedule crea la planificación con los datos calculados
+func (p *planner) schedule() *Schedule {
+
+	var schedule = &Schedule{
+		timelines: p.timelines,
+		warnings:  p.warnings,
+	}
 
 	// Busca la fecha de comienzo y de fin del proyecto que será la menor fecha de inicio de una tarea y la mayor
 	// fecha de fin del proyecto
 	// También calcula la duración total
-	for _, task := range tasks {
-		totalDuration += task.GetDuration()
-		if dateutil.IsLt(task.GetStartDate(), plan.GetStartDate()) {
-			plan.SetStartDate(task.GetStartDate())
+	for i, taskID := range p.scheduled {
+		assignment := p.assignments[taskID]
+		schedule.assignments = append(schedule.assignments, *assignment)
+		schedule.totalDuration += p.tasksIndex[taskID].GetDuration()
+
+		// Un hito representa el comienzo de su día, por lo que para el fin del plan cuenta como el día laborable anterior
+		endDate := assignment.EndDate
+		if assignment.Milestone {
+			endDate = p.calendar.laborableDate(endDate, -1)
 		}
-		if dateutil.IsGt(task.GetEndDate(), plan.GetEndDate()) {
-			plan.SetEndDate(task.GetEndDate())
+
+		if i == 0 || dateutil.IsLt(assignment.StartDate, schedule.startDate) {
+			schedule.startDate = assignment.StartDate
+		}
+		if i == 0 || dateutil.IsGt(endDate, schedule.endDate) {
+			schedule.endDate = endDate
 		}
-		log.Printf("%s %s %d %s %s\n", task.GetID(), task.GetSummary(), task.GetDuration(), task.GetStartDate(), task.GetEndDate())
 	}
 
-	plan.SetWorkdays(CalculateLaborableDays(plan.GetStartDate(), plan.GetEndDate(), feastDays))
-	plan.SetTotalTasks(uint(len(tasks)))
-	plan.SetTotalDuration(totalDuration)
+	schedule.workdays = p.calendar.laborableDays(schedule.startDate, schedule.endDate)
 
-	return nil
+	return schedule
 }
 
-// validateTasks comprueba que las tareas tengan el formato correcto para poder realizar la planificación
-func validateTasks(aTasks []Task, resources []Resource) (map[TaskID]Task, *Error) {
+// assignTask Asigna la tarea a los recursos que en una simulación de planificación elija la estrategia de asignación
+func (p *planner) assignTask(task Task) *Error {
+
 	var (
-		taskIDSErrors []TaskID
-		tasks         = aTasks
+		err               *Error
+		startDate         time.Time
+		minEndDate        time.Time
+		scheduledTaskInfo *scheduledTaskInfo
 	)
 
-	// La lista de tareas no puede estar vacía
-	if len(tasks) == 0 {
-		return nil, newTextError("la lista de tareas a planificar está vacía")
+	// Calcula la fecha de comienzo
+	startDate, minEndDate, err = p.getRealStartDate(task)
+	if err != nil {
+		return err
 	}
 
-	// La lista de recursos no puede estar vacía
-	if len(resources) == 0 {
-		return nil, newTextError("la lista de recursos a asignar está vacía")
+	if IsMilestone(task) {
+		p.assignMilestone(task, startDate, minEndDate)
+		return nil
 	}
 
-	// No puede haber tareas con una duración menor que 1
-	for _, task := range tasks {
-		if task.GetDuration() < 1 {
-			taskIDSErrors = append(taskIDSErrors, task.GetID())
+	// Calcula los datos de la planificación, las tareas divisibles se planifican por tramos
+	if task.IsSplittable() {
+		scheduledTaskInfo, err = p.splitScheduledTask(task, startDate, minEndDate)
+	} else {
+		scheduledTaskInfo, err = p.bestScheduledTask(task, startDate, minEndDate)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.checkConstraint(task, scheduledTaskInfo.StartDate, scheduledTaskInfo.EndDate)
+	p.book(task, scheduledTaskInfo)
+
+	return nil
+}
+
+// book reserva en la agenda de sus recursos las fechas de una tarea planificada, anota el trabajo de cada recurso y
+// guarda su planificación. Si la tarea se ha dividido en tramos se reserva cada tramo por separado
+func (p *planner) book(task Task, info *scheduledTaskInfo) *Assignment {
+
+	var (
+		resourceIDs []ResourceID
+		efforts     []Effort
+	)
+	for i, resource := range info.Team {
+		resourceIDs = append(resourceIDs, resource.GetID())
+		efforts = append(efforts, Effort{
+			ResourceID: resource.GetID(),
+			StartDate:  info.StartDates[i],
+			EndDate:    info.EndDates[i],
+			Days:       float64(info.Efforts[i]) / 100,
+		})
+
+		// Le pone la siguiente fecha de disponibilidad al recurso asignado y reserva las fechas en su agenda
+		p.nextAvailableDates[resource.GetID()] = info.EndDates[i].AddDate(0, 0, 1)
+		if len(info.Segments) == 0 {
+			p.timelines[resource.GetID()] = p.timelines[resource.GetID()].book(Booking{
+				TaskID:    task.GetID(),
+				StartDate: info.StartDates[i],
+				EndDate:   info.EndDates[i],
+			})
 		}
+		p.assignedWorkdays[resource.GetID()] += info.Efforts[i] / 100
 	}
 
-	if len(taskIDSErrors) > 0 {
-		return nil, newError("las siguientes tareas tienen la duración inferior a un día", taskIDSErrors)
+	for _, segment := range info.Segments {
+		p.timelines[segment.ResourceID] = p.timelines[segment.ResourceID].book(Booking{
+			TaskID:    task.GetID(),
+			StartDate: segment.StartDate,
+			EndDate:   segment.EndDate,
+		})
 	}
 
-	// No puede haber tareas con una orden menor que 1
-	for _, task := range tasks {
-		if task.GetOrder() < 1 {
-			taskIDSErrors = append(taskIDSErrors, task.GetID())
+	assignment := &Assignment{
+		TaskID:      task.GetID(),
+		ResourceID:  info.Resource.GetID(),
+		ResourceIDs: resourceIDs,
+		Efforts:     efforts,
+		StartDate:   info.StartDate,
+		EndDate:     info.EndDate,
+		Segments:    info.Segments,
+	}
+	p.assignments[task.GetID()] = assignment
+	p.scheduled = append(p.scheduled, task.GetID())
+
+	return assignment
+}
+
+// assignMilestone Planifica un hito. Un hito no tiene recurso ni duración, su fecha es el primer día laborable del plan
+// en el que se cumplen sus dependencias y representa el comienzo de ese día
+func (p *planner) assignMilestone(task Task, startDate time.Time, minEndDate time.Time) {
+
+	var date = p.startDate
+
+	if dateutil.IsGt(startDate, date) {
+		date = startDate
+	}
+
+	// Si debe terminar después de una fecha, el hito será como pronto el día laborable siguiente
+	if !minEndDate.IsZero() {
+		if nextDate := p.calendar.laborableDate(minEndDate, 1); dateutil.IsGt(nextDate, date) {
+			date = nextDate
 		}
 	}
 
-	if len(taskIDSErrors) > 0 {
-		return nil, newError("las siguientes tareas tienen un orden inferior a 1", taskIDSErrors)
+	if !p.calendar.isLaborableDay(date) {
+		date = p.calendar.laborableDate(date, 1)
+	}
+
+	p.checkConstraint(task, date, date)
+
+	p.assignments[task.GetID()] = &Assignment{
+		TaskID:    task.GetID(),
+		StartDate: date,
+		EndDate:   date,
+		Milestone: true,
 	}
+	p.scheduled = append(p.scheduled, task.GetID())
+}
+
+// getRealStartDate Retorna la fecha real de comienzo de una tarea teniendo en cuenta que si tiene bloqueos
+// calcula la fecha mayor de las que bloquean a la tarea, ya que no se podrá empezar antes.
+// También retorna la fecha mínima en la que podrá terminar la tarea por las dependencias FinishToFinish y
+// StartToFinish, o una fecha vacía si no tiene ninguna.
+// No usa la fecha de comienzo que ya tenga la tarea, solo sus dependencias y su restricción de fecha.
+func (p *planner) getRealStartDate(task Task) (time.Time, time.Time, *Error) {
 
-	// Ha de haber tareas de tipo 'ResourceType' para los tipos de recurso de tipo 'Type' y viceversa
 	var (
-		typeOfTasks     = make(map[string]bool)
-		typeOfResources = make(map[string]bool)
+		blocksBy   = p.blocksBy[task.GetID()]
+		startDate  time.Time
+		minEndDate time.Time
 	)
 
-	// Crea el índice de tipos de tarea
-	for _, task := range tasks {
-		typeOfTasks[task.GetResourceType()] = true
-	}
+	// Las tareas que la bloquean en una lista de tareas ordenadas primero por las que bloquean a otros deberían
+	// estar ya planificadas, si no lo están damos error
+	for _, dep := range blocksBy {
 
-	// Crea el índice de tipos de resource
-	for _, resource := range resources {
-		typeOfResources[resource.GetType()] = true
-	}
+		assignment := p.assignments[dep.GetTaskID()]
 
-	// Tiene que haber tareas para los tipos de recurso que llegan
-	for _, resource := range resources {
-		if _, exist := typeOfTasks[resource.GetType()]; !exist {
-			return nil, newTextError("no existen tareas para el recurso %s de tipo %s", resource.GetID(), resource.GetType())
+		if assignment == nil {
+			// Esto debería ser muy improbable que se dé si el código funciona como debe...
+			return time.Time{}, time.Time{}, newTextError(ErrUnplannedTask, "La tarea %s no está planificada y bloquea a la tarea %s que está en planificación",
+				dep.GetTaskID(), task.GetID())
 		}
-	}
 
-	// Tiene que haber recursos para las tareas del tipo que llevan asociado
-	for _, task := range tasks {
-		if _, exist := typeOfResources[task.GetResourceType()]; !exist {
-			taskIDSErrors = append(taskIDSErrors, task.GetID())
+		depStartDate, depMinEndDate := p.dependencyDates(dep, assignment)
+		if dateutil.IsGt(depStartDate, startDate) {
+			startDate = depStartDate
+		}
+		if dateutil.IsGt(depMinEndDate, minEndDate) {
+			minEndDate = depMinEndDate
 		}
-	}
-	if len(taskIDSErrors) > 0 {
-		return nil, newError("no hay recursos para los tipos de estas tareas", taskIDSErrors)
 	}
 
-	// Crea el índice de tareas para poder saber a qué tarea corresponde un tareaID
-	var tasksIndex = make(map[TaskID]Task, len(tasks))
-	for _, task := range tasks {
-		tasksIndex[task.GetID()] = task
+	// Las restricciones StartNoEarlierThan y MustStartOn impiden que comience antes de su fecha
+	if task.GetConstraintType() == StartNoEarlierThan || task.GetConstraintType() == MustStartOn {
+		if date := task.GetConstraintDate().Local(); dateutil.IsGt(date, startDate) {
+			startDate = date
+		}
 	}
 
-	var tasksNotExists = map[TaskID]bool{}
+	return startDate, minEndDate, nil
 
-	// No puede haber tareas en blocksBy ni blocksTo que no estén en la lista de tareas
-	for _, task := range tasks {
+}
 
-		// Revisa las tareas a las que bloquea
-		for _, dep := range task.GetBlocksTo() {
-			if _, exist := tasksIndex[dep.GetTaskID()]; !exist {
-				tasksNotExists[dep.GetTaskID()] = true
-			}
-		}
+// dependencyDates devuelve la fecha mínima de comienzo y la fecha mínima de fin que impone una dependencia a la tarea
+// bloqueada según su tipo, vacías si no impone ninguna. Los desfases se cuentan en días laborables
+func (p *planner) dependencyDates(dep TaskDependency, assignment *Assignment) (time.Time, time.Time) {
 
-		// Revisa las tareas que le bloquean
-		for _, dep := range task.GetBlocksBy() {
-			if _, exist := tasksIndex[dep.GetTaskID()]; !exist {
-				tasksNotExists[dep.GetTaskID()] = true
-			}
+	var startDate, minEndDate time.Time
+
+	switch dep.GetType() {
+	case StartToStart:
+		startDate = p.calendar.laborableDate(assignment.StartDate, dep.GetLag())
+	case FinishToFinish:
+		// Un hito termina al comienzo de su día por lo que la tarea puede terminar el día laborable anterior
+		lag := dep.GetLag()
+		if assignment.Milestone {
+			lag--
+		}
+		minEndDate = p.calendar.laborableDate(assignment.EndDate, lag)
+	case StartToFinish:
+		// Debe terminar como muy pronto el día laborable anterior al comienzo de la tarea que la bloquea
+		minEndDate = p.calendar.laborableDate(assignment.StartDate, dep.GetLag()-1)
+	default:
+		// Debe comenzar como pronto el día siguiente de la fecha de fin de la tarea que la bloquea o el mismo día
+		// del hito que la bloquea
+		switch {
+		case assignment.Milestone:
+			startDate = p.calendar.laborableDate(assignment.EndDate, dep.GetLag())
+		case dep.GetLag() != 0:
+			startDate = p.calendar.laborableDate(assignment.EndDate, dep.GetLag()+1)
+		default:
+			startDate = assignment.EndDate.AddDate(0, 0, 1)
 		}
 	}
 
-	for taskID := range tasksNotExists {
-		taskIDSErrors = append(taskIDSErrors, taskID)
+	return startDate, minEndDate
+}
+
+// checkConstraint anota la tarea como que no cumple su restricción de fecha si no la cumple con esas fechas. La tarea se
+// planifica igualmente y al terminar la planificación se devuelve un error con todas las que no la cumplen
+func (p *planner) checkConstraint(task Task, startDate time.Time, endDate time.Time) {
+	if !p.meetsConstraint(task, startDate, endDate) {
+		p.unmetConstraints = append(p.unmetConstraints, task.GetID())
 	}
+}
+
+// meetsConstraint devuelve True si la planificación de una tarea cumple su restricción de fecha. En los hitos la
+// fecha de fin es el día laborable anterior ya que representan el comienzo de su día. Al planificar hacia delante
+// StartNoEarlierThan siempre se cumple, hacia atrás o en un plan modificado a mano no
+func (p *planner) meetsConstraint(task Task, startDate time.Time, endDate time.Time) bool {
 
-	if len(taskIDSErrors) > 0 {
-		return nil, newError("hay tareas bloquedas o que bloquean a otras que no existen en la lista de tareas", taskIDSErrors)
+	var constraintDate = task.GetConstraintDate().Local()
+
+	if IsMilestone(task) {
+		endDate = p.calendar.laborableDate(endDate, -1)
 	}
 
-	// No puede haber referencias circulares, es decir, tareas que se bloqueen a sí mismas
-	for _, task := range tasks {
-		err := checkForCircularDependencies(task, tasksIndex, []TaskID{})
-		if err != nil {
-			return nil, err
-		}
+	switch task.GetConstraintType() {
+	case StartNoEarlierThan:
+		return dateutil.IsGte(startDate, constraintDate)
+	case StartNoLaterThan:
+		return dateutil.IsLte(startDate, constraintDate)
+	case FinishNoLaterThan:
+		return dateutil.IsLte(endDate, constraintDate)
+	case MustStartOn:
+		return dateutil.IsEqual(startDate, constraintDate)
 	}
 
-	// No puede haber tareas con un orden superior que bloqueen a otras con un número de orden inferior o igual
-	for _, task := range tasks {
-		for _, dep := range task.GetBlocksTo() {
-			taskBlocked := tasksIndex[dep.GetTaskID()]
-			if taskBlocked != nil {
-				if task.GetOrder() >= taskBlocked.GetOrder() {
-					taskIDSErrors = append(taskIDSErrors, dep.GetTaskID())
-				}
+	return true
+}
+
+// bestScheduledTask Calcula la planificación de la tarea para cada recurso y retorna la que elija la estrategia de
+// asignación del plan. Si la tarea necesita varios recursos a la vez los elige uno a uno para cada demanda y después
+// planifica la tarea para todos ellos juntos.
+func (p *planner) bestScheduledTask(task Task, startDate time.Time, minEndDate time.Time) (*scheduledTaskInfo, *Error) {
+
+	var (
+		team          []teamMember
+		chosen        = make(map[ResourceID]bool)
+		bestScheduled *scheduledTaskInfo
+		err           *Error
+	)
+
+	if task.GetMaxTeamSize() > 1 {
+		return p.bestEffortTeam(task, startDate, minEndDate)
+	}
+
+	for _, demand := range taskDemands(task) {
+		for n := uint(0); n < demand.GetQuantity(); n++ {
+			bestScheduled, err = p.chooseResource(task, demand.GetResourceType(), chosen, startDate, minEndDate)
+			if err != nil {
+				return nil, err
 			}
+			chosen[bestScheduled.Resource.GetID()] = true
+			team = append(team, teamMember{resource: bestScheduled.Resource, taskType: demand.GetResourceType()})
 		}
 	}
 
-	if len(taskIDSErrors) > 0 {
-		return nil, newError("tareas bloqueadas por tareas con un orden superior", taskIDSErrors)
+	// Si son varios recursos la tarea se planifica cuando estén libres todos a la vez, para lo que tienen que trabajar
+	// algún día de la semana en común
+	if len(team) > 1 {
+		var calendars []*workCalendar
+		for _, member := range team {
+			calendars = append(calendars, p.resourceProfiles[member.resource.GetID()].calendar)
+		}
+		if !shareWorkingWeekday(calendars) {
+			return nil, newError(ErrUnschedulable, "los recursos elegidos para las siguientes tareas no tienen ningún día laborable en común",
+				[]TaskID{task.GetID()})
+		}
+		bestScheduled = p.scheduledTask(task, team, startDate, minEndDate)
+		if bestScheduled.Incomplete {
+			return nil, newError(ErrUnschedulable, "las siguientes tareas no se pueden terminar antes de que sus recursos dejen de estar disponibles",
+				[]TaskID{task.GetID()})
+		}
 	}
 
-	return tasksIndex, nil
+	return bestScheduled, nil
 }
 
-// checkForCircularDependencies Chequea que no haya referencias circulares en los bloqueos de las tareas, es decir que
-// una tarea se bloquee a sí misma.
-// Por ejemplo: A → B → C → A ...
-func checkForCircularDependencies(task Task, tasksIndex map[TaskID]Task, linkedblocksTo []TaskID) *Error {
+// bestEffortTeam planifica una tarea cuyo esfuerzo se puede repartir entre varios recursos de su tipo. Va añadiendo
+// recursos al equipo elegidos por la estrategia de asignación hasta el máximo de la tarea y se queda con el equipo con
+// el que la tarea termina antes. A igualdad de fecha de fin prefiere el equipo más pequeño
+func (p *planner) bestEffortTeam(task Task, startDate time.Time, minEndDate time.Time) (*scheduledTaskInfo, *Error) {
 
-	for _, tID := range linkedblocksTo {
-		if tID == task.GetID() {
-			return newTextError("%s contiene una referencia circular en su cadena de dependencias", tID)
-		}
+	var (
+		team          []teamMember
+		chosen        = make(map[ResourceID]bool)
+		bestScheduled *scheduledTaskInfo
+		maxTeamSize   = int(task.GetMaxTeamSize())
+	)
+
+	// El equipo no puede ser mayor que el número de recursos que pueden realizar la tarea
+	eligible := len(p.eligibleResources(task, task.GetResourceType(), nil))
+	if eligible == 0 {
+		return nil, newError(ErrNotEnoughResources, "no hay suficientes recursos para las siguientes tareas", []TaskID{task.GetID()})
 	}
+	if eligible < maxTeamSize {
+		maxTeamSize = eligible
+	}
+
+	for len(team) < maxTeamSize {
 
-	// Recorre las tareas a las que está bloqueando llamando recursivamente a la propia función
-	for _, dep := range task.GetBlocksTo() {
-		// Añade task.ID a la secuencia de bloqueos para detectar una posible referencia circular a ella misma en
-		// dicho encadenamiento
-		linkedblocksTo = append(linkedblocksTo, task.GetID())
-		err := checkForCircularDependencies(tasksIndex[dep.GetTaskID()], tasksIndex, linkedblocksTo)
+		// Si no quedan recursos que puedan entrar en el equipo se queda con el mejor equipo encontrado hasta ahora
+		member, err := p.chooseResource(task, task.GetResourceType(), chosen, startDate, minEndDate)
 		if err != nil {
-			return err
+			if bestScheduled == nil {
+				return nil, err
+			}
+			break
+		}
+		chosen[member.Resource.GetID()] = true
+		team = append(team, teamMember{resource: member.Resource, taskType: task.GetResourceType()})
+
+		scheduled := p.effortScheduledTask(task, team, startDate, minEndDate)
+		if scheduled.Incomplete {
+			continue
+		}
+		if bestScheduled == nil || dateutil.IsLt(scheduled.EndDate, bestScheduled.EndDate) {
+			bestScheduled = scheduled
 		}
 	}
-	return nil
+
+	if bestScheduled == nil {
+		return nil, newError(ErrUnschedulable, "las siguientes tareas no se pueden terminar antes de que sus recursos dejen de estar disponibles",
+			[]TaskID{task.GetID()})
+	}
+
+	return bestScheduled, nil
 }
 
-// assignTask Asigna la tarea al recurso que en una simulación de planificación pueda acabarla antes
-func assignTask(task Task, resources []Resource, feastDays []Holidays, tasksIndex map[TaskID]Task) *Error {
+// eligibleResources devuelve los recursos que pueden realizar un tipo de tarea y que no estén ya elegidos ni excluidos
+// de la tarea. Si la tarea necesita un recurso que puede realizar el tipo de tarea y aun no se ha elegido solo devuelve
+// ese recurso
+func (p *planner) eligibleResources(task Task, taskType string, chosen map[ResourceID]bool) []Resource {
 
 	var (
-		err               *Error
-		startDate         time.Time
-		scheduledTaskInfo *scheduledTaskInfo
+		resources []Resource
+		required  = task.GetRequiredResourceID()
 	)
 
-	// Le asigna la fecha de comienzo
-	startDate, err = getRealStartDate(task, tasksIndex)
-	if err != nil {
-		return err
+	if required != nil && (chosen[*required] || !p.resourceProfiles[*required].canDo(taskType)) {
+		required = nil
 	}
 
-	task.SetStartDate(startDate)
+	for _, resource := range p.resources {
+		if chosen[resource.GetID()] || !p.resourceProfiles[resource.GetID()].canDo(taskType) ||
+			containsResourceID(task.GetExcludedResourceIDs(), resource.GetID()) ||
+			(required != nil && *required != resource.GetID()) {
+			continue
+		}
+		resources = append(resources, resource)
+	}
 
-	// Le asigna los datos de la planificación
-	scheduledTaskInfo = bestScheduledTask(task, resources, feastDays)
+	return resources
+}
 
-	var resourceID = scheduledTaskInfo.Resource.GetID()
-	task.SetResourceID(&resourceID)
-	task.SetStartDate(scheduledTaskInfo.StartDate)
-	task.SetEndDate(scheduledTaskInfo.EndDate)
+// chooseResource calcula la planificación de la tarea para cada recurso de eligibleResources y retorna la que elija la
+// estrategia de asignación del plan
+func (p *planner) chooseResource(task Task, taskType string, chosen map[ResourceID]bool, startDate time.Time,
+	minEndDate time.Time) (*scheduledTaskInfo, *Error) {
+
+	var scheduledTasks []*scheduledTaskInfo
+
+	for _, resource := range p.eligibleResources(task, taskType, chosen) {
+		scheduledTasks = append(scheduledTasks, p.scheduledTask(task,
+			[]teamMember{{resource: resource, taskType: taskType}}, startDate, minEndDate))
+	}
+
+	if len(scheduledTasks) == 0 {
+		return nil, newError(ErrNotEnoughResources, "no hay suficientes recursos para las siguientes tareas", []TaskID{task.GetID()})
+	}
+
+	// Solo se tienen en cuenta los recursos que pueden terminar la tarea antes de dejar de estar disponibles y entre
+	// ellos elige la estrategia de asignación
+	return p.pickCandidate(task, scheduledTasks,
+		"las siguientes tareas no se pueden terminar antes de que sus recursos dejen de estar disponibles",
+		func(scheduledTasks []*scheduledTaskInfo) (*scheduledTaskInfo, *Error) {
+			candidates := make([]Candidate, 0, len(scheduledTasks))
+			for _, sh := range scheduledTasks {
+				candidates = append(candidates, Candidate{
+					ResourceID:       sh.Resource.GetID(),
+					StartDate:        sh.StartDate,
+					EndDate:          sh.EndDate,
+					AssignedWorkdays: p.assignedWorkdays[sh.Resource.GetID()],
+					Predecessor:      p.isPredecessorResource(task, sh.Resource.GetID()),
+					Cost:             sh.Resource.GetCost(),
+				})
+			}
 
-	return nil
+			index := p.strategy.Choose(task, candidates)
+			if index < 0 || index >= len(candidates) {
+				return nil, newError(ErrUnknownResource, "la estrategia de asignación ha elegido un recurso que no existe para las siguientes tareas",
+					[]TaskID{task.GetID()})
+			}
+
+			return scheduledTasks[index], nil
+		})
 }
 
-// getRealStartDate Retorna la fecha real de comienzo de una tarea teniendo en cuenta que si tiene bloqueos
-// calcula la fecha mayor de las que bloquean a la tarea, ya que no se podrá empezar antes.
-func getRealStartDate(task Task, tasksIndex map[TaskID]Task) (time.Time, *Error) {
+// pickCandidate elige entre las planificaciones de una tarea con cada recurso candidato. Solo tiene en cuenta las de los
+// recursos que pueden terminar la tarea, si no hay ninguno devuelve un error con el mensaje unschedulable. De ellas se
+// queda con las que cumplen la restricción de fecha de la tarea y con las de sus recursos preferidos, si hay alguna, y
+// elige una con choose
+func (p *planner) pickCandidate(task Task, scheduledTasks []*scheduledTaskInfo, unschedulable string,
+	choose func([]*scheduledTaskInfo) (*scheduledTaskInfo, *Error)) (*scheduledTaskInfo, *Error) {
 
-	var blocksBy = task.GetBlocksBy()
+	var complete []*scheduledTaskInfo
+	for _, sh := range scheduledTasks {
+		if !sh.Incomplete {
+			complete = append(complete, sh)
+		}
+	}
+	if len(complete) == 0 {
+		return nil, newError(ErrUnschedulable, unschedulable, []TaskID{task.GetID()})
+	}
+	scheduledTasks = complete
+
+	// Si con alguno de los recursos se cumple la restricción de fecha de la tarea solo se tienen en cuenta esos
+	var meetingConstraint []*scheduledTaskInfo
+	for _, sh := range scheduledTasks {
+		if p.meetsConstraint(task, sh.StartDate, sh.EndDate) {
+			meetingConstraint = append(meetingConstraint, sh)
+		}
+	}
+	if len(meetingConstraint) > 0 {
+		scheduledTasks = meetingConstraint
+	}
 
-	// Si no tiene tareas que la bloqueen retorna la misma fecha de comienzo
-	if len(blocksBy) == 0 {
-		return task.GetStartDate(), nil
+	// Si alguno de los recursos preferidos puede realizar la tarea se elige entre ellos
+	var preferred []*scheduledTaskInfo
+	for _, sh := range scheduledTasks {
+		if containsResourceID(task.GetPreferredResourceIDs(), sh.Resource.GetID()) {
+			preferred = append(preferred, sh)
+		}
+	}
+	if len(preferred) > 0 {
+		scheduledTasks = preferred
 	}
 
-	var tasksBlocksBy []Task
+	return choose(scheduledTasks)
+}
 
-	// Las tareas que la bloquean en una lista de tareas ordenadas primero por las que bloquean a otros deberían
-	// estar ya planificadas, si no lo están damos error
-	for _, dep := range blocksBy {
+// isPredecessorResource devuelve True si el recurso tiene asignada alguna de las tareas que bloquean a la tarea
+func (p *planner) isPredecessorResource(task Task, resourceID ResourceID) bool {
+	for _, dep := range p.blocksBy[task.GetID()] {
+		if assignment := p.assignments[dep.GetTaskID()]; assignment != nil {
+			for _, id := range assignment.ResourceIDs {
+				if id == resourceID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
 
-		taskBlocksBy := tasksIndex[dep.GetTaskID()]
+// teamMember recurso que trabaja en una tarea y tipo de tarea que realiza en ella
+type teamMember struct {
+	resource Resource
+	taskType string
+}
 
-		if taskBlocksBy.GetResourceID() == nil {
-			// Esto debería ser muy improbable que se dé si el código funciona como debe...
-			return time.Time{}, newTextError("La tarea %s no está planificada y bloquea a la tarea %s que está en planificación",
-				dep.GetTaskID(), task.GetID())
+// scheduledTask planifica una tarea para uno o varios recursos que trabajan a la vez. La duración de la tarea se
+// reparte en los días laborables de los recursos según su porcentaje de dedicación y su rendimiento en el tipo de
+// tarea. Si minEndDate no está vacía retrasa el comienzo de la tarea hasta que su fecha de fin sea igual o superior a
+// ella.
+// En modo BackfillScheduling la tarea se coloca en el primer hueco de la agenda de los recursos en el que quepa, si no
+// se coloca a continuación de la última tarea asignada a cada recurso
+func (p *planner) scheduledTask(task Task, team []teamMember, startDate time.Time, minEndDate time.Time) *scheduledTaskInfo {
+
+	var (
+		realStartDate = startDate
+		endDate       time.Time
+		complete      bool
+		resources     []Resource
+	)
+
+	// Si la fecha en la que estaría disponible alguno de los recursos es superior a la fecha en la que debe comenzar
+	// la tarea se pone esa fecha para el cálculo
+	for _, member := range team {
+		resources = append(resources, member.resource)
+
+		nextAvailableDate := p.nextAvailableDates[member.resource.GetID()]
+		if p.schedulingMode == BackfillScheduling {
+			nextAvailableDate = p.availableFrom[member.resource.GetID()]
+		}
+		if dateutil.IsGt(nextAvailableDate, realStartDate) {
+			realStartDate = nextAvailableDate
 		}
+	}
 
-		tasksBlocksBy = append(tasksBlocksBy, taskBlocksBy)
+	for {
+		realStartDate, endDate, complete = p.fitTask(task, team, realStartDate, minEndDate)
+
+		if !complete || p.schedulingMode != BackfillScheduling {
+			break
+		}
+
+		// Si se solapa con alguna tarea de la agenda de los recursos se intenta en el hueco siguiente a esa tarea
+		var booking *Booking
+		for _, member := range team {
+			if booking = p.timelines[member.resource.GetID()].overlap(realStartDate, endDate); booking != nil {
+				break
+			}
+		}
+		if booking == nil {
+			break
+		}
+		realStartDate = booking.EndDate.AddDate(0, 0, 1)
 	}
 
-	// Ordena blocksBy por fecha de fin de tarea descendiente para encontrar la fecha mayor
-	sort.Slice(tasksBlocksBy, func(i, j int) bool {
-		return dateutil.IsGt(tasksBlocksBy[i].GetEndDate(), tasksBlocksBy[j].GetEndDate())
-	})
+	var (
+		startDates = make([]time.Time, 0, len(team))
+		endDates   = make([]time.Time, 0, len(team))
+		efforts    = make([]uint, 0, len(team))
+	)
+	for range team {
+		startDates = append(startDates, realStartDate)
+		endDates = append(endDates, endDate)
+		efforts = append(efforts, task.GetDuration()*100)
+	}
 
-	// Retorna la fecha mayor + 1 día ya que debe comenzar al día siguiente de la fecha de fin de la última tarea que la bloquean
-	return tasksBlocksBy[0].GetEndDate().AddDate(0, 0, 1), nil
+	return &scheduledTaskInfo{
+		Resource:   resources[0],
+		Team:       resources,
+		StartDates: startDates,
+		EndDates:   endDates,
+		Efforts:    efforts,
+		StartDate:  realStartDate,
+		EndDate:    endDate,
+		Incomplete: !complete,
+	}
 
 }
 
-// bestScheduledTask Calcula la planificación de la tarea para cada recurso y retorna la que termine antes.
-func bestScheduledTask(task Task, resources []Resource, feastDays []Holidays) *scheduledTaskInfo {
+// effortScheduledTask planifica una tarea cuyo esfuerzo se reparte entre varios recursos. Cada recurso empieza a
+// trabajar en la tarea en cuanto está disponible y todos trabajan hasta que se completa su duración. Si minEndDate no
+// está vacía retrasa el comienzo de la tarea hasta que su fecha de fin sea igual o superior a ella.
+// En modo BackfillScheduling cada recurso empieza en el primer hueco de su agenda en el que quepa su parte
+func (p *planner) effortScheduledTask(task Task, team []teamMember, startDate time.Time, minEndDate time.Time) *scheduledTaskInfo {
 
 	var (
-		availableResources []Resource
-		scheduledTasks     []*scheduledTaskInfo
-		bestScheduled      *scheduledTaskInfo
+		profiles   []*resourceProfile
+		taskTypes  []string
+		startDates = make([]time.Time, len(team))
+		endDates   []time.Time
+		efforts    []uint
+		endDate    time.Time
+		complete   bool
 	)
 
-	// Filtra los recursos del tipo de tarea
-	for _, resource := range resources {
-		if resource.GetType() == task.GetResourceType() {
-			availableResources = append(availableResources, resource)
+	for i, member := range team {
+		profiles = append(profiles, p.resourceProfiles[member.resource.GetID()])
+		taskTypes = append(taskTypes, member.taskType)
+
+		nextAvailableDate := p.nextAvailableDates[member.resource.GetID()]
+		if p.schedulingMode == BackfillScheduling {
+			nextAvailableDate = p.availableFrom[member.resource.GetID()]
+		}
+		startDates[i] = startDate
+		if dateutil.IsGt(nextAvailableDate, startDate) {
+			startDates[i] = nextAvailableDate
 		}
 	}
 
-	// Calcula la planificación de la tarea para cada recurso
-	for _, resource := range availableResources {
-		scheduledTasks = append(scheduledTasks, scheduledTask(task, resource, feastDays))
-	}
-	// Busca la mejor fecha
-	for _, sh := range scheduledTasks {
-		if bestScheduled == nil {
-			bestScheduled = sh
-		} else {
-			if dateutil.IsLt(sh.EndDate, bestScheduled.EndDate) {
-				bestScheduled = sh
+	for {
+		startDates, endDates, efforts, endDate, complete = fitEffort(task, profiles, taskTypes, startDates)
+		if !complete {
+			break
+		}
+
+		// Si termina antes de lo que le permiten sus dependencias se retrasa el comienzo un día
+		if !minEndDate.IsZero() && dateutil.IsLt(endDate, minEndDate) {
+			startDate = startDate.AddDate(0, 0, 1)
+			for i := range startDates {
+				if dateutil.IsLt(startDates[i], startDate) {
+					startDates[i] = startDate
+				}
 			}
+			continue
+		}
+
+		if p.schedulingMode != BackfillScheduling {
+			break
+		}
+
+		// Si algún recurso se solapa con alguna tarea de su agenda empieza en el hueco siguiente a esa tarea
+		var overlapped bool
+		for i, member := range team {
+			if efforts[i] == 0 {
+				continue
+			}
+			if booking := p.timelines[member.resource.GetID()].overlap(startDates[i], endDates[i]); booking != nil {
+				startDates[i] = booking.EndDate.AddDate(0, 0, 1)
+				overlapped = true
+			}
+		}
+		if !overlapped {
+			break
 		}
 	}
 
-	// Le pone la fecha siguiente fecha de disponibilidad al recurso asignado
-	bestScheduled.Resource.SetNextAvailableDate(bestScheduled.EndDate.AddDate(0, 0, 1))
+	// Solo forman parte del equipo los recursos que llegan a trabajar en la tarea
+	var scheduled = &scheduledTaskInfo{EndDate: endDate, Incomplete: !complete}
+	for i, member := range team {
+		if efforts[i] == 0 {
+			continue
+		}
+		if len(scheduled.Team) == 0 || dateutil.IsLt(startDates[i], scheduled.StartDate) {
+			scheduled.StartDate = startDates[i]
+		}
+		scheduled.Team = append(scheduled.Team, member.resource)
+		scheduled.StartDates = append(scheduled.StartDates, startDates[i])
+		scheduled.EndDates = append(scheduled.EndDates, endDates[i])
+		scheduled.Efforts = append(scheduled.Efforts, efforts[i])
+	}
+	if len(scheduled.Team) == 0 {
+		scheduled.Team = []Resource{team[0].resource}
+		scheduled.StartDates = []time.Time{startDates[0]}
+		scheduled.EndDates = []time.Time{endDates[0]}
+		scheduled.Efforts = []uint{efforts[0]}
+	}
+	scheduled.Resource = scheduled.Team[0]
 
-	return bestScheduled
+	return scheduled
 }
 
-// scheduledTask planifica una tarea para un recurso
-func scheduledTask(task Task, resource Resource, feastDays []Holidays) *scheduledTaskInfo {
+// fitEffort reparte la duración de una tarea entre varios recursos que empiezan a trabajar en ella en las fechas
+// indicadas. Retorna el primer y el último día que trabaja cada recurso, el trabajo que hace cada uno en porcentaje de
+// jornadas y la fecha en la que se termina la tarea. Si los recursos dejan de estar disponibles antes de terminarla
+// retorna false
+func fitEffort(task Task, profiles []*resourceProfile, taskTypes []string, startDates []time.Time) ([]time.Time, []time.Time, []uint, time.Time, bool) {
 
 	var (
-		holidaysAndFeastDays []Holidays
-		realStartDate        time.Time
-		duration             uint
-		endDate              time.Time
+		until          = effortAvailableUntil(profiles)
+		pendingWork    = task.GetDuration() * 100
+		efforts        = make([]uint, len(profiles))
+		realStartDates = make([]time.Time, len(profiles))
+		endDates       = make([]time.Time, len(profiles))
+		day            = startDates[0]
 	)
 
-	// concatena días de fiesta y vacaciones del recurso
-	holidaysAndFeastDays = append(holidaysAndFeastDays, resource.GetHolidays()...)
-	holidaysAndFeastDays = append(holidaysAndFeastDays, feastDays...)
-
-	// Si la fecha en la que debe comenzar la tarea es superior a la fecha en la que el recurso estaría disponible
-	// Ponemos esa fecha como fecha en la que el recurso estaría disponible para el cálculo y si no se pone la fecha en
-	// la que estaría disponible el recurso
-	if dateutil.IsGt(task.GetStartDate(), resource.GetNextAvailableDate()) {
-		realStartDate = task.GetStartDate()
-	} else {
-		realStartDate = resource.GetNextAvailableDate()
+	for _, date := range startDates {
+		if dateutil.IsLt(date, day) {
+			day = date
+		}
 	}
 
-	duration = task.GetDuration()
-	endDate = realStartDate
+	copy(realStartDates, startDates)
 
 	for {
+		if !until.IsZero() && dateutil.IsGt(day, until) {
+			return realStartDates, endDates, efforts, day, false
+		}
 
-		if IsLaborableDay(endDate, holidaysAndFeastDays) {
-			// Si es el primer día que empieza a contar actualiza la fecha de comienzo, puede que aunque la fecha de
-			// comienzo inicial sea hoy, hoy y mañana sean fiesta por lo que comenzaría dos días después
-			if duration == task.GetDuration() {
-				realStartDate = endDate
+		for i, profile := range profiles {
+			if dateutil.IsLt(day, startDates[i]) {
+				continue
 			}
-			duration--
-			if duration == 0 {
-				break
+
+			capacity := profile.taskCapacity(day, taskTypes[i])
+			if capacity == 0 {
+				continue
 			}
+
+			// El primer día que trabaja el recurso es su fecha de comienzo
+			if efforts[i] == 0 {
+				realStartDates[i] = day
+			}
+			endDates[i] = day
+
+			if capacity >= pendingWork {
+				efforts[i] += pendingWork
+				return realStartDates, endDates, efforts, day, true
+			}
+			efforts[i] += capacity
+			pendingWork -= capacity
 		}
-		endDate = endDate.AddDate(0, 0, 1)
+		day = day.AddDate(0, 0, 1)
 	}
+}
 
-	return &scheduledTaskInfo{
-		Resource:  resource,
-		StartDate: realStartDate,
-		EndDate:   endDate,
+// fitTask calcula las fechas de comienzo y fin de una tarea para uno o varios recursos que trabajan a la vez
+// comenzando como pronto en startDate y terminando como pronto en minEndDate, si no está vacía. Si alguno de los
+// recursos deja de estar disponible antes de terminarla retorna false
+func (p *planner) fitTask(task Task, team []teamMember, startDate time.Time, minEndDate time.Time) (time.Time, time.Time, bool) {
+
+	var (
+		profiles      []*resourceProfile
+		taskTypes     []string
+		realStartDate = startDate
+		pendingWork   uint
+		endDate       time.Time
+	)
+
+	for _, member := range team {
+		profiles = append(profiles, p.resourceProfiles[member.resource.GetID()])
+		taskTypes = append(taskTypes, member.taskType)
+	}
+
+	var until = teamAvailableUntil(profiles)
+
+	for {
+
+		// El trabajo pendiente se cuenta en porcentaje de jornadas, una jornada completa es 100
+		pendingWork = task.GetDuration() * 100
+		endDate = realStartDate
+
+		for {
+
+			if !until.IsZero() && dateutil.IsGt(endDate, until) {
+				return realStartDate, endDate, false
+			}
+
+			if capacity := teamCapacity(profiles, taskTypes, endDate); capacity > 0 {
+				// Si es el primer día que empieza a contar actualiza la fecha de comienzo, puede que aunque la fecha de
+				// comienzo inicial sea hoy, hoy y mañana sean fiesta por lo que comenzaría dos días después
+				if pendingWork == task.GetDuration()*100 {
+					realStartDate = endDate
+				}
+				if capacity >= pendingWork {
+					break
+				}
+				pendingWork -= capacity
+			}
+			endDate = endDate.AddDate(0, 0, 1)
+		}
+
+		// Si termina antes de lo que le permiten sus dependencias se retrasa el comienzo un día
+		if minEndDate.IsZero() || dateutil.IsGte(endDate, minEndDate) {
+			break
+		}
+		realStartDate = realStartDate.AddDate(0, 0, 1)
 	}
 
+	return realStartDate, endDate, true
 }
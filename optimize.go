@@ -0,0 +1,204 @@
+package gplan
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// defaultOptimizeIterations iteraciones de la búsqueda si no se indica otro número
+const defaultOptimizeIterations = 1000
+
+// OptimizeOptions opciones de la búsqueda de una planificación que termine antes
+type OptimizeOptions struct {
+	// Número máximo de planificaciones alternativas que se prueban, si es 0 se prueban 1000
+	Iterations uint
+	// Semilla de los números aleatorios, con la misma semilla y el mismo número de iteraciones el resultado es siempre
+	// el mismo
+	Seed int64
+}
+
+// Optimize busca una planificación del plan que termine antes que la de PlanSchedule. Parte de la planificación de
+// PlanSchedule y en cada iteración prueba un cambio aleatorio sobre la mejor solución encontrada: intercambia la
+// prioridad de dos tareas o fuerza el recurso de una tarea. Se queda con el cambio si el plan no termina más tarde y,
+// si terminan a la vez, si la suma de las fechas de fin de las tareas no es mayor.
+// La búsqueda termina al completar las iteraciones o al cancelarse el contexto, por ejemplo al vencer su plazo, y
+// devuelve la mejor planificación encontrada hasta ese momento. Solo devuelve error si falla la planificación inicial
+func Optimize(ctx context.Context, startDate time.Time, plan ProjectPlan, options OptimizeOptions) (*Schedule, *Error) {
+
+	var (
+		tasks     = plan.GetTasks()
+		resources = plan.GetResources()
+		rnd       = rand.New(rand.NewSource(options.Seed))
+		eligible  = make(map[TaskID][]ResourceID)
+		movable   []TaskID
+	)
+
+	if options.Iterations == 0 {
+		options.Iterations = defaultOptimizeIterations
+	}
+
+	// La solución inicial es la de PlanSchedule, con las tareas en el orden en el que se han planificado y la
+	// estrategia de asignación del plan
+	bestSchedule, err := PlanSchedule(startDate, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	best := &optimizedPlan{
+		ProjectPlan: plan,
+		choices:     make(map[TaskID]ResourceID),
+	}
+	for _, assignment := range bestSchedule.GetAssignments() {
+		best.order = append(best.order, assignment.TaskID)
+	}
+
+	if len(tasks) == 0 || len(resources) == 0 {
+		return bestSchedule, nil
+	}
+
+	// Solo se puede forzar a una tarea un recurso que pueda realizarla, y solo tiene sentido en las tareas que pueden
+	// realizar varios recursos
+	p := newPlanner(startDate, plan, make(map[TaskID]Task))
+	for _, task := range tasks {
+		for _, demand := range taskDemands(task) {
+			for _, resource := range p.eligibleResources(task, demand.GetResourceType(), nil) {
+				if !containsResourceID(eligible[task.GetID()], resource.GetID()) {
+					eligible[task.GetID()] = append(eligible[task.GetID()], resource.GetID())
+				}
+			}
+		}
+		if len(eligible[task.GetID()]) > 1 {
+			movable = append(movable, task.GetID())
+		}
+	}
+
+	for i := uint(0); i < options.Iterations && ctx.Err() == nil; i++ {
+
+		candidate := best.clone()
+
+		if len(movable) == 0 || (rnd.Intn(2) == 0 && len(candidate.order) > 1) {
+			// Intercambia la prioridad de dos tareas
+			a, b := rnd.Intn(len(candidate.order)), rnd.Intn(len(candidate.order))
+			candidate.order[a], candidate.order[b] = candidate.order[b], candidate.order[a]
+		} else {
+			// Fuerza el recurso de una tarea entre los que pueden realizarla
+			taskID := movable[rnd.Intn(len(movable))]
+			candidate.choices[taskID] = eligible[taskID][rnd.Intn(len(eligible[taskID]))]
+		}
+
+		schedule, err := PlanSchedule(startDate, candidate)
+		if err != nil {
+			continue
+		}
+
+		if !isWorseSchedule(schedule, bestSchedule) {
+			best, bestSchedule = candidate, schedule
+		}
+	}
+
+	return bestSchedule, nil
+}
+
+// isWorseSchedule devuelve True si una planificación termina más tarde que otra o, si terminan a la vez, si la suma
+// de las fechas de fin de sus tareas es mayor
+func isWorseSchedule(schedule *Schedule, other *Schedule) bool {
+
+	if !dateutil.IsEqual(schedule.GetEndDate(), other.GetEndDate()) {
+		return dateutil.IsGt(schedule.GetEndDate(), other.GetEndDate())
+	}
+
+	return sumEndDates(schedule) > sumEndDates(other)
+}
+
+// sumEndDates devuelve la suma de los días que hay desde el comienzo de una planificación hasta el fin de cada tarea
+func sumEndDates(schedule *Schedule) int64 {
+	var sum int64
+	for _, assignment := range schedule.GetAssignments() {
+		sum += int64(assignment.EndDate.Sub(schedule.GetStartDate()).Hours() / 24)
+	}
+	return sum
+}
+
+// optimizedPlan plan con otro orden de las tareas y con el recurso de algunas tareas forzado
+type optimizedPlan struct {
+	ProjectPlan
+	// IDs de las tareas en el orden en el que se deben planificar
+	order []TaskID
+	// Recurso elegido para cada tarea, si es posible asignárselo
+	choices map[TaskID]ResourceID
+}
+
+// clone devuelve una copia del plan que no comparte el orden ni los recursos elegidos
+func (p *optimizedPlan) clone() *optimizedPlan {
+	c := &optimizedPlan{
+		ProjectPlan: p.ProjectPlan,
+		order:       append([]TaskID{}, p.order...),
+		choices:     make(map[TaskID]ResourceID, len(p.choices)),
+	}
+	for taskID, resourceID := range p.choices {
+		c.choices[taskID] = resourceID
+	}
+	return c
+}
+
+// GetTasks devuelve las tareas del plan con el número de orden de su posición
+func (p *optimizedPlan) GetTasks() []Task {
+
+	var (
+		tasks      []Task
+		tasksIndex = make(map[TaskID]Task)
+	)
+
+	for _, task := range p.ProjectPlan.GetTasks() {
+		tasksIndex[task.GetID()] = task
+	}
+	for i, taskID := range p.order {
+		tasks = append(tasks, &orderedTask{Task: tasksIndex[taskID], order: uint(i + 1)})
+	}
+
+	return tasks
+}
+
+// GetPriorityRule el orden de las tareas lo decide la búsqueda
+func (p *optimizedPlan) GetPriorityRule() PriorityRule {
+	return ManualOrderRule
+}
+
+// GetAssignmentStrategy elige el recurso forzado de cada tarea y si no lo hay usa la estrategia del plan
+func (p *optimizedPlan) GetAssignmentStrategy() AssignmentStrategy {
+	fallback := p.ProjectPlan.GetAssignmentStrategy()
+	if fallback == nil {
+		fallback = EarliestFinishStrategy{}
+	}
+	return &choiceStrategy{choices: p.choices, fallback: fallback}
+}
+
+// orderedTask tarea con otro número de orden
+type orderedTask struct {
+	Task
+	order uint
+}
+
+func (t *orderedTask) GetOrder() uint {
+	return t.order
+}
+
+// choiceStrategy estrategia de asignación que elige el recurso forzado de cada tarea si es uno de los candidatos
+type choiceStrategy struct {
+	choices  map[TaskID]ResourceID
+	fallback AssignmentStrategy
+}
+
+func (s *choiceStrategy) Choose(task Task, candidates []Candidate) int {
+	if resourceID, exist := s.choices[task.GetID()]; exist {
+		for i, candidate := range candidates {
+			if candidate.ResourceID == resourceID {
+				return i
+			}
+		}
+	}
+	return s.fallback.Choose(task, candidates)
+}
@@ -0,0 +1,79 @@
+package gplan_test
+
+import (
+	"context"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optimize", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		tasks := []*Task{
+			NewTask("Corta", "Summary", "backend", 10, 1),
+			NewTask("Larga", "Summary", "backend", 20, 3),
+			NewTask("QA", "Summary", "qa", 30, 2),
+		}
+
+		BlocksTo(tasks[1], tasks[2])
+
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("optimizamos una planificación", func() {
+
+		It("Debe encontrar una planificación que termine antes", func() {
+			schedule, err := gplan.Optimize(context.Background(), parseDate("2021-06-07"), plan,
+				gplan.OptimizeOptions{Iterations: 100, Seed: 1})
+			Expect(err).Should(BeNil())
+
+			Expect(schedule.GetEndDate()).Should(Equal(parseDate("2021-06-11")))
+			larga, _ := schedule.GetAssignment("Larga")
+			Expect(larga.StartDate).Should(Equal(parseDate("2021-06-07")))
+		})
+
+		It("Con la misma semilla debe devolver la misma planificación", func() {
+			options := gplan.OptimizeOptions{Iterations: 50, Seed: 7}
+
+			first, err := gplan.Optimize(context.Background(), parseDate("2021-06-07"), plan, options)
+			Expect(err).Should(BeNil())
+			second, err := gplan.Optimize(context.Background(), parseDate("2021-06-07"), plan, options)
+			Expect(err).Should(BeNil())
+
+			Expect(second.GetAssignments()).Should(Equal(first.GetAssignments()))
+		})
+
+		It("No debe modificar el plan", func() {
+			_, err := gplan.Optimize(context.Background(), parseDate("2021-06-07"), plan,
+				gplan.OptimizeOptions{Iterations: 100, Seed: 1})
+			Expect(err).Should(BeNil())
+
+			Expect(plan.Tasks[0].Order).Should(BeEquivalentTo(10))
+			Expect(plan.Tasks[0].StartDate.IsZero()).Should(BeTrue())
+		})
+	})
+
+	When("el contexto se cancela", func() {
+
+		It("Debe devolver la mejor planificación encontrada hasta ese momento", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			schedule, err := gplan.Optimize(ctx, parseDate("2021-06-07"), plan, gplan.OptimizeOptions{Seed: 1})
+			Expect(err).Should(BeNil())
+
+			// No ha dado tiempo a mejorar la planificación inicial
+			Expect(schedule.GetEndDate()).Should(Equal(parseDate("2021-06-14")))
+		})
+	})
+
+})
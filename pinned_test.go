@@ -0,0 +1,109 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequiredResource", func() {
+
+	var (
+		tasks     []*Task
+		resources []*Resource
+	)
+
+	resourceID := func(id gplan.ResourceID) *gplan.ResourceID {
+		return &id
+	}
+
+	BeforeEach(func() {
+		resources = []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "maquetacion", parseDate("2021-06-07"), nil),
+		}
+
+		tasks = []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 2),
+			NewTask("Tarea2", "Summary", "backend", 20, 3),
+			NewTask("Tarea3", "Summary", "backend", 30, 1),
+			NewTask("Tarea4", "Summary", "backend", 40, 1),
+			NewTask("Tarea5", "Summary", "maquetacion", 50, 1),
+		}
+	})
+
+	When("las tareas indican qué recursos las deben realizar", func() {
+
+		It("Deben asignarse al recurso necesario, preferir los preferidos y evitar los excluidos", func() {
+			tasks[0].RequiredResourceID = resourceID("cslopez")
+			tasks[2].PreferredResourceIDs = []gplan.ResourceID{"cslopez"}
+			tasks[3].ExcludedResourceIDs = []gplan.ResourceID{"ahg"}
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-08 cslopez",
+				"2021-06-07 2021-06-09 ahg",
+				"2021-06-09 2021-06-09 cslopez",
+				"2021-06-10 2021-06-10 cslopez",
+				"2021-06-07 2021-06-07 Noemi",
+			})
+		})
+
+		It("Si no hay recursos preferidos del tipo de la tarea debe elegir cualquiera", func() {
+			tasks[2].PreferredResourceIDs = []gplan.ResourceID{"Noemi"}
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+			Expect(*plan.Tasks[2].ResourceID).Should(BeEquivalentTo("ahg"))
+		})
+	})
+
+	When("el recurso necesario no existe", func() {
+
+		It("Debe devolver un error", func() {
+			tasks[1].RequiredResourceID = resourceID("nadie")
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas necesitan un recurso que no existe")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea2"}))
+		})
+	})
+
+	When("el recurso necesario no puede realizar la tarea", func() {
+
+		It("Debe devolver un error si es de otro tipo o está excluido", func() {
+			tasks[1].RequiredResourceID = resourceID("Noemi")
+			tasks[3].RequiredResourceID = resourceID("ahg")
+			tasks[3].ExcludedResourceIDs = []gplan.ResourceID{"ahg"}
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas necesitan un recurso que no puede realizarlas")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea2", "Tarea4"}))
+		})
+	})
+
+	When("todos los recursos del tipo de una tarea están excluidos", func() {
+
+		It("Debe devolver un error", func() {
+			tasks[4].ExcludedResourceIDs = []gplan.ResourceID{"Noemi"}
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("no hay suficientes recursos para las siguientes tareas")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea5"}))
+		})
+	})
+
+})
@@ -0,0 +1,184 @@
+package gplan
+
+import (
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// PriorityRule regla con la que se elige qué tarea se planifica primero de entre las que ya tienen planificadas todas
+// las tareas que las bloquean. Si la regla no distingue entre dos tareas se planifica primero la de menor número de
+// orden
+type PriorityRule uint
+
+const (
+	// ManualOrderRule las tareas se planifican por número de orden
+	ManualOrderRule PriorityRule = iota
+	// LongestPathRule primero la tarea con la cadena de tareas más larga hasta el fin del plan, sumando su duración y
+	// la de las tareas a las que bloquea
+	LongestPathRule
+	// MostSuccessorsRule primero la tarea que bloquea a más tareas, directa o indirectamente
+	MostSuccessorsRule
+	// ShortestTaskRule primero la tarea de menor duración
+	ShortestTaskRule
+	// EarliestConstraintRule primero la tarea con la restricción de fecha más temprana, las tareas sin restricción de
+	// fecha van después
+	EarliestConstraintRule
+)
+
+// PlanScheduleBestRule calcula la planificación de un plan con PlanSchedule con cada una de las reglas de prioridad y
+// devuelve la que antes termina y la regla con la que se ha calculado. Si terminan a la vez se queda con la primera en
+// el orden de las constantes de PriorityRule. Ignora la regla de prioridad del plan
+func PlanScheduleBestRule(startDate time.Time, plan ProjectPlan) (*Schedule, PriorityRule, *Error) {
+
+	var (
+		bestSchedule *Schedule
+		bestRule     PriorityRule
+		firstErr     *Error
+	)
+
+	for rule := ManualOrderRule; rule <= EarliestConstraintRule; rule++ {
+		schedule, err := PlanSchedule(startDate, &rulePlan{ProjectPlan: plan, rule: rule})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if bestSchedule == nil || dateutil.IsLt(schedule.GetEndDate(), bestSchedule.GetEndDate()) {
+			bestSchedule, bestRule = schedule, rule
+		}
+	}
+
+	if bestSchedule == nil {
+		return nil, ManualOrderRule, firstErr
+	}
+
+	return bestSchedule, bestRule, nil
+}
+
+// rulePlan plan con otra regla de prioridad
+type rulePlan struct {
+	ProjectPlan
+	rule PriorityRule
+}
+
+func (p *rulePlan) GetPriorityRule() PriorityRule {
+	return p.rule
+}
+
+// priorityComparator devuelve la función que compara la prioridad de dos tareas según una regla de prioridad. Devuelve
+// un número negativo si la primera tiene más prioridad, positivo si la tiene la segunda y 0 si la regla no las distingue.
+// Devuelve un error si la regla necesita recorrer las cadenas de tareas y hay dependencias circulares
+func priorityComparator(rule PriorityRule, tasksIndex map[TaskID]Task, successors map[TaskID][]TaskID) (func(a, b TaskID) int, *Error) {
+
+	switch rule {
+	case LongestPathRule:
+		var (
+			lengths  = make(map[TaskID]uint, len(tasksIndex))
+			visiting = make(map[TaskID]bool)
+		)
+		for taskID := range tasksIndex {
+			if _, ok := longestPath(taskID, tasksIndex, successors, lengths, visiting); !ok {
+				return nil, newTextError(ErrCircularDependency,
+					"no se puede calcular la cadena más larga porque hay tareas con dependencias circulares")
+			}
+		}
+		return func(a, b TaskID) int {
+			return compareUint(lengths[b], lengths[a])
+		}, nil
+
+	case MostSuccessorsRule:
+		var counts = make(map[TaskID]uint, len(tasksIndex))
+		for taskID := range tasksIndex {
+			counts[taskID] = countSuccessors(taskID, successors)
+		}
+		return func(a, b TaskID) int {
+			return compareUint(counts[b], counts[a])
+		}, nil
+
+	case ShortestTaskRule:
+		return func(a, b TaskID) int {
+			return compareUint(tasksIndex[a].GetDuration(), tasksIndex[b].GetDuration())
+		}, nil
+
+	case EarliestConstraintRule:
+		return func(a, b TaskID) int {
+			var (
+				hasA = tasksIndex[a].GetConstraintType() != NoConstraint
+				hasB = tasksIndex[b].GetConstraintType() != NoConstraint
+			)
+			switch {
+			case hasA && hasB:
+				dateA, dateB := tasksIndex[a].GetConstraintDate().Local(), tasksIndex[b].GetConstraintDate().Local()
+				if dateutil.IsLt(dateA, dateB) {
+					return -1
+				}
+				if dateutil.IsGt(dateA, dateB) {
+					return 1
+				}
+			case hasA:
+				return -1
+			case hasB:
+				return 1
+			}
+			return 0
+		}, nil
+	}
+
+	return func(a, b TaskID) int {
+		return 0
+	}, nil
+}
+
+// longestPath calcula la duración de la cadena de tareas más larga que empieza en una tarea, guardando en lengths la
+// de todas las tareas que recorre. En visiting están las tareas de la cadena que se está recorriendo, si vuelve a una
+// de ellas hay una dependencia circular y devuelve false
+func longestPath(taskID TaskID, tasksIndex map[TaskID]Task, successors map[TaskID][]TaskID, lengths map[TaskID]uint,
+	visiting map[TaskID]bool) (uint, bool) {
+
+	if length, exist := lengths[taskID]; exist {
+		return length, true
+	}
+	if visiting[taskID] {
+		return 0, false
+	}
+
+	visiting[taskID] = true
+	defer delete(visiting, taskID)
+
+	var longest uint
+	for _, succ := range successors[taskID] {
+		length, ok := longestPath(succ, tasksIndex, successors, lengths, visiting)
+		if !ok {
+			return 0, false
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+
+	lengths[taskID] = tasksIndex[taskID].GetDuration() + longest
+	return lengths[taskID], true
+}
+
+// countSuccessors devuelve el número de tareas a las que bloquea una tarea, directa o indirectamente
+func countSuccessors(taskID TaskID, successors map[TaskID][]TaskID) uint {
+
+	var (
+		visited = make(map[TaskID]bool)
+		pending = append([]TaskID{}, successors[taskID]...)
+	)
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		pending = append(pending, successors[current]...)
+	}
+
+	return uint(len(visited))
+}
@@ -0,0 +1,138 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Priority", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		tasks := []*Task{
+			NewTask("Corta", "Summary", "backend", 10, 1),
+			NewTask("Larga", "Summary", "backend", 20, 3),
+			NewTask("QA", "Summary", "qa", 30, 2),
+		}
+
+		BlocksTo(tasks[1], tasks[2])
+
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("planificamos con la regla por número de orden", func() {
+
+		It("Las tareas deben planificarse por número de orden", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-07 ahg",
+				"2021-06-08 2021-06-10 ahg",
+				"2021-06-11 2021-06-14 Noemi",
+			})
+		})
+	})
+
+	When("planificamos con la regla de la cadena más larga", func() {
+
+		It("Debe planificarse antes la tarea con la cadena más larga", func() {
+			plan.PriorityRule = gplan.LongestPathRule
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-10 2021-06-10 ahg",
+				"2021-06-07 2021-06-09 ahg",
+				"2021-06-10 2021-06-11 Noemi",
+			})
+		})
+	})
+
+	When("planificamos con la regla de la cadena más larga y hay dependencias circulares", func() {
+
+		It("Debe devolver un error", func() {
+			plan.Tasks[0].BlocksBy = []*TaskDependency{NewTaskDependency("QA")}
+			plan.Tasks[2].BlocksBy = append(plan.Tasks[2].BlocksBy, NewTaskDependency("Corta"))
+			plan.PriorityRule = gplan.LongestPathRule
+
+			_, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Kind).Should(Equal(gplan.ErrCircularDependency))
+		})
+	})
+
+	When("planificamos con la regla de más sucesoras", func() {
+
+		It("Debe planificarse antes la tarea que bloquea a más tareas", func() {
+			plan.PriorityRule = gplan.MostSuccessorsRule
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			larga, _ := schedule.GetAssignment("Larga")
+			Expect(larga.StartDate).Should(Equal(parseDate("2021-06-07")))
+		})
+	})
+
+	When("planificamos con la regla de la tarea más corta", func() {
+
+		It("Debe planificarse antes la tarea más corta", func() {
+			plan.Tasks[0].Order = 40
+			plan.PriorityRule = gplan.ShortestTaskRule
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			corta, _ := schedule.GetAssignment("Corta")
+			Expect(corta.StartDate).Should(Equal(parseDate("2021-06-07")))
+		})
+	})
+
+	When("planificamos con la regla de la restricción más temprana", func() {
+
+		It("Debe planificarse antes la tarea con restricción de fecha", func() {
+			plan.Tasks[1].SetConstraint(gplan.StartNoEarlierThan, parseDate("2021-06-07"))
+			plan.PriorityRule = gplan.EarliestConstraintRule
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			larga, _ := schedule.GetAssignment("Larga")
+			Expect(larga.StartDate).Should(Equal(parseDate("2021-06-07")))
+		})
+	})
+
+	When("buscamos la regla con la que antes termina el plan", func() {
+
+		It("Debe devolver la planificación que antes termina y su regla", func() {
+			schedule, rule, err := gplan.PlanScheduleBestRule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(rule).Should(Equal(gplan.LongestPathRule))
+			Expect(schedule.GetEndDate()).Should(Equal(parseDate("2021-06-11")))
+		})
+	})
+
+	When("la regla de prioridad es desconocida", func() {
+
+		It("Debe devolver un error", func() {
+			plan.PriorityRule = gplan.PriorityRule(99)
+
+			_, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("la regla de prioridad del plan es desconocida")))
+		})
+	})
+
+})
@@ -0,0 +1,210 @@
+package gplan
+
+import (
+	"sort"
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// Booking intervalo de días ocupado por una tarea en la agenda de un recurso
+type Booking struct {
+	// ID de la tarea
+	TaskID TaskID
+	// Fecha de comienzo de la tarea
+	StartDate time.Time
+	// Fecha de fin de la tarea
+	EndDate time.Time
+}
+
+// resourceTimeline agenda de un recurso, intervalos ocupados ordenados por fecha de comienzo
+type resourceTimeline []Booking
+
+// book añade un intervalo a la agenda manteniendo el orden por fecha de comienzo
+func (t resourceTimeline) book(booking Booking) resourceTimeline {
+	i := sort.Search(len(t), func(i int) bool {
+		return dateutil.IsGt(t[i].StartDate, booking.StartDate)
+	})
+	t = append(t, Booking{})
+	copy(t[i+1:], t[i:])
+	t[i] = booking
+	return t
+}
+
+// overlap devuelve el primer intervalo de la agenda que se solapa con las fechas indicadas, o nil si no hay ninguno
+func (t resourceTimeline) overlap(startDate time.Time, endDate time.Time) *Booking {
+	for i := range t {
+		if dateutil.IsLte(t[i].StartDate, endDate) && dateutil.IsGte(t[i].EndDate, startDate) {
+			return &t[i]
+		}
+	}
+	return nil
+}
+
+// resourceProfile contiene lo necesario para saber cuánto puede trabajar un recurso cada día: su calendario efectivo y
+// sus porcentajes de dedicación
+type resourceProfile struct {
+	// Calendario del recurso, incluye sus vacaciones y los festivos del plan
+	calendar *workCalendar
+	// Porcentajes de dedicación ordenados por fecha
+	allocations []Allocation
+	// Rendimiento del recurso por cada tipo de tarea que puede realizar
+	efficiencies map[string]uint
+	// Último día en el que está disponible, vacía si lo está indefinidamente
+	availableUntil time.Time
+}
+
+// resourceEfficiencies devuelve el rendimiento de un recurso por cada tipo de tarea que puede realizar, su tipo más
+// los de sus habilidades. El rendimiento de cada habilidad se multiplica por el rendimiento general del recurso
+func resourceEfficiencies(resource Resource) map[string]uint {
+
+	var efficiency = resource.GetEfficiency()
+	if efficiency == 0 {
+		efficiency = 100
+	}
+
+	var efficiencies = map[string]uint{resource.GetType(): efficiency}
+
+	for _, skill := range resource.GetSkills() {
+		skillEfficiency := skill.GetEfficiency()
+		if skillEfficiency == 0 {
+			skillEfficiency = 100
+		}
+		efficiencies[skill.GetType()] = efficiency * skillEfficiency / 100
+	}
+
+	return efficiencies
+}
+
+// newResourceProfile crea el perfil de trabajo de un recurso de un plan
+func newResourceProfile(plan ProjectPlan, resource Resource) *resourceProfile {
+
+	profile := &resourceProfile{
+		calendar:       newResourceCalendar(plan, resource),
+		allocations:    append([]Allocation{}, resource.GetAllocations()...),
+		efficiencies:   resourceEfficiencies(resource),
+		availableUntil: resource.GetAvailableUntil().Local(),
+	}
+
+	sort.SliceStable(profile.allocations, func(i, j int) bool {
+		return dateutil.IsLt(profile.allocations[i].GetFrom(), profile.allocations[j].GetFrom())
+	})
+
+	return profile
+}
+
+// capacity devuelve el porcentaje de la jornada que puede trabajar el recurso en un día, 0 si no es laborable
+func (r *resourceProfile) capacity(day time.Time) uint {
+
+	if !r.calendar.isLaborableDay(day) || r.isGone(day) {
+		return 0
+	}
+
+	var percentage uint = 100
+	for _, allocation := range r.allocations {
+		if dateutil.IsGt(allocation.GetFrom(), day) {
+			break
+		}
+		percentage = allocation.GetPercentage()
+	}
+
+	return percentage
+}
+
+// isGone devuelve True si el recurso ya no está disponible en un día porque ha pasado su último día de disponibilidad
+func (r *resourceProfile) isGone(day time.Time) bool {
+	return !r.availableUntil.IsZero() && dateutil.IsGt(day, r.availableUntil)
+}
+
+// canDo devuelve True si el recurso puede realizar tareas de un tipo
+func (r *resourceProfile) canDo(taskType string) bool {
+	_, exist := r.efficiencies[taskType]
+	return exist
+}
+
+// taskCapacity devuelve el trabajo que el recurso puede hacer en un día en una tarea de un tipo en porcentaje de
+// jornada, teniendo en cuenta su dedicación y su rendimiento en ese tipo de tareas
+func (r *resourceProfile) taskCapacity(day time.Time, taskType string) uint {
+
+	capacity := r.capacity(day) * r.efficiencies[taskType] / 100
+
+	// Si trabaja ese día siempre avanza algo, para que una dedicación y un rendimiento muy bajos no la bloqueen
+	if capacity == 0 && r.capacity(day) > 0 {
+		capacity = 1
+	}
+
+	return capacity
+}
+
+// demandedType devuelve el primero de los tipos de recurso que necesita una tarea que puede realizar el recurso
+func (r *resourceProfile) demandedType(task Task) string {
+	for _, demand := range taskDemands(task) {
+		if r.canDo(demand.GetResourceType()) {
+			return demand.GetResourceType()
+		}
+	}
+	return task.GetResourceType()
+}
+
+// teamAvailableUntil devuelve el último día en el que pueden trabajar juntos varios recursos, el primero en el que deja
+// de estar disponible alguno de ellos, o una fecha vacía si todos están disponibles indefinidamente
+func teamAvailableUntil(profiles []*resourceProfile) time.Time {
+	var until time.Time
+	for _, profile := range profiles {
+		if !profile.availableUntil.IsZero() && (until.IsZero() || dateutil.IsLt(profile.availableUntil, until)) {
+			until = profile.availableUntil
+		}
+	}
+	return until
+}
+
+// effortAvailableUntil devuelve el último día en el que alguno de varios recursos puede trabajar en una tarea cuya
+// duración se reparte entre ellos, o una fecha vacía si alguno está disponible indefinidamente
+func effortAvailableUntil(profiles []*resourceProfile) time.Time {
+	var until time.Time
+	for _, profile := range profiles {
+		if profile.availableUntil.IsZero() {
+			return time.Time{}
+		}
+		if dateutil.IsGt(profile.availableUntil, until) {
+			until = profile.availableUntil
+		}
+	}
+	return until
+}
+
+// teamCapacity devuelve el trabajo que pueden hacer en un día en una tarea varios recursos que trabajan a la vez, que
+// es el del recurso que menos puede trabajar ese día. Cada recurso trabaja en el tipo de tarea de su misma posición
+func teamCapacity(profiles []*resourceProfile, taskTypes []string, day time.Time) uint {
+	var capacity uint
+	for i, profile := range profiles {
+		memberCapacity := profile.taskCapacity(day, taskTypes[i])
+		if i == 0 || memberCapacity < capacity {
+			capacity = memberCapacity
+		}
+	}
+	return capacity
+}
+
+// teamWork devuelve el trabajo realizado en una tarea por varios recursos que trabajan a la vez entre dos fechas,
+// incluidas ambas, en porcentaje de jornadas. Es decir, dos días al 50% devuelven 100
+func teamWork(profiles []*resourceProfile, taskTypes []string, from time.Time, to time.Time) uint {
+	var work uint
+	for date := from; dateutil.IsLte(date, to); date = date.AddDate(0, 0, 1) {
+		work += teamCapacity(profiles, taskTypes, date)
+	}
+	return work
+}
+
+// effortWork devuelve el trabajo realizado hasta una fecha, incluida, por varios recursos entre los que se reparte la
+// duración de una tarea, cada uno desde la fecha en la que empieza a trabajar en ella, en porcentaje de jornadas. Es la
+// suma del trabajo de todos ellos
+func effortWork(profiles []*resourceProfile, taskTypes []string, from []time.Time, to time.Time) uint {
+	var work uint
+	for i, profile := range profiles {
+		for date := from[i]; dateutil.IsLte(date, to); date = date.AddDate(0, 0, 1) {
+			work += profile.taskCapacity(date, taskTypes[i])
+		}
+	}
+	return work
+}
@@ -13,14 +13,14 @@ import (
 func Review(plan ProjectPlan, reviewDate time.Time) *Error {
 
 	var (
-		tasks     = plan.GetTasks()
-		feastDays = plan.GetFeastDays()
+		tasks    = plan.GetTasks()
+		calendar = newPlanCalendar(plan)
 	)
 
 	// Si el plan no está planificado aun retorna error, aprovecha el bucle para convertir las tareas a Local
 	for _, task := range tasks {
-		if task.GetResourceID() == nil {
-			return newTextError("Hay tareas sin planificar aun")
+		if !isPlanned(task) {
+			return newTextError(ErrUnplannedTask, "Hay tareas sin planificar aun")
 		}
 		task.SetStartDate(task.GetStartDate().Local())
 		task.SetEndDate(task.GetEndDate().Local())
@@ -41,7 +41,7 @@ func Review(plan ProjectPlan, reviewDate time.Time) *Error {
 	// Calcula el total de tareas completadas
 	CalculateTotalTasksCompleted(plan)
 
-	plan.SetWorkdaysToEndDate(CalculateLaborableDays(time.Now(), plan.GetEndDate(), feastDays))
+	plan.SetWorkdaysToEndDate(calendar.laborableDays(time.Now(), plan.GetEndDate()))
 
 	plan.SetReviewDate(reviewDate)
 
@@ -55,7 +55,6 @@ func Review(plan ProjectPlan, reviewDate time.Time) *Error {
 func CalculateExpectedProgress(plan ProjectPlan, reviewDate time.Time) {
 	var (
 		expectedProgressDuration uint
-		feastDays                = plan.GetFeastDays()
 		resourcesIdx             = make(map[ResourceID]Resource)
 	)
 
@@ -66,7 +65,15 @@ func CalculateExpectedProgress(plan ProjectPlan, reviewDate time.Time) {
 
 	for _, task := range plan.GetTasks() {
 
-		if dateutil.IsLte(reviewDate, plan.GetStartDate()) || dateutil.IsLte(reviewDate, task.GetStartDate()) {
+		if IsMilestone(task) {
+			// Un hito no tiene duración, debería estar alcanzado si la fecha de revisión es igual o posterior a la del hito
+			if dateutil.IsGte(reviewDate, task.GetStartDate()) {
+				task.SetExpectedProgress(100)
+			} else {
+				task.SetExpectedProgress(0)
+			}
+			task.SetExpectedCompleteDuration(0)
+		} else if dateutil.IsLte(reviewDate, plan.GetStartDate()) || dateutil.IsLte(reviewDate, task.GetStartDate()) {
 			// Si la fecha de revisión es <= que la fecha de comienzo del plan o que la fecha de comienzo de la tarea
 			// debería estar al 0%
 			task.SetExpectedProgress(0)
@@ -79,25 +86,111 @@ func CalculateExpectedProgress(plan ProjectPlan, reviewDate time.Time) {
 		} else {
 			// Si la fecha de revisión está entre la fecha de inicio y la de fin de la tarea, calcula el progreso esperado en base a la duración
 			// que debería llevar
-			var holidaysAndFeastDays []Holidays
-			// concatena días de fiesta y vacaciones del recurso
-			var resource = resourcesIdx[*task.GetResourceID()]
-			holidaysAndFeastDays = append(holidaysAndFeastDays, resource.GetHolidays()...)
-			holidaysAndFeastDays = append(holidaysAndFeastDays, feastDays...)
-
-			currDays := CalculateLaborableDays(task.GetStartDate(), reviewDate.AddDate(0, 0, -1), holidaysAndFeastDays)
-			task.SetExpectedProgress((currDays * 100) / task.GetDuration())
-			task.SetExpectedCompleteDuration(currDays)
+			// Usa el perfil de los recursos que incluye sus vacaciones, los días de fiesta, su porcentaje de dedicación y
+			// su rendimiento en el tipo de tarea. Si son varios y trabajan a la vez la tarea avanza al ritmo del más lento
+			var (
+				profiles  []*resourceProfile
+				taskTypes []string
+			)
+			for _, resourceID := range taskResourceIDs(task) {
+				profile := newResourceProfile(plan, resourcesIdx[resourceID])
+				profiles = append(profiles, profile)
+				taskTypes = append(taskTypes, profile.demandedType(task))
+			}
+
+			// El trabajo se cuenta en porcentaje de jornadas, una jornada completa es 100. Si la duración se reparte
+			// entre los recursos se suma el trabajo de todos ellos
+			var currWork uint
+			if segments := task.GetSegments(); len(segments) > 0 {
+				// Si la tarea está dividida en tramos se suma el trabajo de cada tramo con el recurso que lo realiza
+				for _, segment := range segments {
+					profile := newResourceProfile(plan, resourcesIdx[segment.ResourceID])
+					to := reviewDate.AddDate(0, 0, -1)
+					if dateutil.IsLt(segment.EndDate, to) {
+						to = segment.EndDate
+					}
+					currWork += teamWork([]*resourceProfile{profile}, []string{profile.demandedType(task)}, segment.StartDate, to)
+				}
+			} else if efforts := plannedEfforts(task); task.GetMaxTeamSize() > 1 && efforts != nil {
+				// Si la tarea guarda el trabajo de cada recurso se suma lo que cada uno debería llevar en sus fechas
+				to := reviewDate.AddDate(0, 0, -1)
+				for i, effort := range efforts {
+					effortTo := to
+					if dateutil.IsLt(effort.EndDate, effortTo) {
+						effortTo = effort.EndDate
+					}
+					work := teamWork(profiles[i:i+1], taskTypes[i:i+1], effort.StartDate, effortTo)
+					if share := uint(effort.Days * 100); work > share {
+						work = share
+					}
+					currWork += work
+				}
+			} else if task.GetMaxTeamSize() > 1 {
+				// Si no lo guarda se estima cuándo empieza a trabajar cada recurso
+				var startDates []time.Time
+				for _, resourceID := range taskResourceIDs(task) {
+					startDates = append(startDates, effortStartDate(plan, task, resourcesIdx[resourceID]))
+				}
+				currWork = effortWork(profiles, taskTypes, startDates, reviewDate.AddDate(0, 0, -1))
+			} else {
+				currWork = teamWork(profiles, taskTypes, task.GetStartDate(), reviewDate.AddDate(0, 0, -1))
+			}
+			if currWork > task.GetDuration()*100 {
+				currWork = task.GetDuration() * 100
+			}
+			task.SetExpectedProgress(currWork / task.GetDuration())
+			task.SetExpectedCompleteDuration(currWork / 100)
 		}
 
 		expectedProgressDuration += task.GetExpectedCompleteDuration()
 	}
 
+	// Si el plan solo tiene hitos no hay duración con la que ponderar, debería estar al 100% si se han alcanzado todos
+	if plan.GetTotalDuration() == 0 {
+		plan.SetExpectedProgress(milestonesProgress(plan, Task.GetExpectedProgress))
+		return
+	}
+
 	// Se suman las duraciones que deberían estar completas o a medio completar y se calcula el % con respecto al
 	// total de la duración
 	plan.SetExpectedProgress((expectedProgressDuration * 100) / plan.GetTotalDuration())
 }
 
+// milestonesProgress devuelve el avance de un plan sin duración, es decir, con solo hitos: 100% si todos los hitos
+// tienen el avance completo y 0% si no
+func milestonesProgress(plan ProjectPlan, progress func(Task) uint) uint {
+	for _, task := range plan.GetTasks() {
+		if progress(task) < 100 {
+			return 0
+		}
+	}
+	return 100
+}
+
+// effortStartDate devuelve la fecha en la que un recurso empieza a trabajar en una tarea cuya duración se reparte
+// entre varios recursos: cuando está disponible y ha terminado sus tareas anteriores
+func effortStartDate(plan ProjectPlan, task Task, resource Resource) time.Time {
+
+	var startDate = task.GetStartDate()
+
+	if availableFrom := resource.GetAvailableFrom().Local(); dateutil.IsGt(availableFrom, startDate) {
+		startDate = availableFrom
+	}
+
+	for _, other := range plan.GetTasks() {
+		if other.GetID() == task.GetID() || IsMilestone(other) || !dateutil.IsLt(other.GetEndDate(), task.GetEndDate()) {
+			continue
+		}
+		for _, resourceID := range taskResourceIDs(other) {
+			if resourceID == resource.GetID() && !dateutil.IsLt(other.GetEndDate(), startDate) {
+				startDate = other.GetEndDate().AddDate(0, 0, 1)
+			}
+		}
+	}
+
+	return startDate
+}
+
 // CalculateRealProgress Calcula el % de avance real
 func CalculateRealProgress(plan ProjectPlan) {
 
@@ -106,12 +199,24 @@ func CalculateRealProgress(plan ProjectPlan) {
 	)
 
 	for _, task := range plan.GetTasks() {
+		// Los hitos no tienen duración por lo que no cuentan para el avance ponderado por duración
+		if IsMilestone(task) {
+			task.SetRealCompleteDuration(0)
+			continue
+		}
+
 		// Calcula la duración real completada de la tarea en función del % realcompletado
 		task.SetRealCompleteDuration((task.GetDuration() * task.GetRealProgress()) / 100)
 
 		totalCompleteXDuration += task.GetRealCompleteDuration()
 	}
 
+	// Si el plan solo tiene hitos estará al 100% si se han alcanzado todos
+	if plan.GetTotalDuration() == 0 {
+		plan.SetRealProgress(milestonesProgress(plan, Task.GetRealProgress))
+		return
+	}
+
 	plan.SetRealProgress(totalCompleteXDuration * 100 / plan.GetTotalDuration())
 }
 
@@ -120,7 +225,7 @@ func CalculateRealProgress(plan ProjectPlan) {
 func CalculateProgressDays(plan ProjectPlan, reviewDate time.Time) {
 
 	var (
-		feastDays                = plan.GetFeastDays()
+		calendar                 = newPlanCalendar(plan)
 		expectedCompleteDuration float64
 		realCompleteDuration     float64
 		realProgressDays         float64
@@ -141,14 +246,14 @@ func CalculateProgressDays(plan ProjectPlan, reviewDate time.Time) {
 		// Si la fecha en de la última resolución es > que la fecha de fin de planificación
 		if dateutil.IsGt(realEndDate, plan.GetEndDate()) {
 			// Calcula los días que van desde la fecha final + 1 y la fecha de resolución y serán días de retraso
-			realProgressDays = float64(CalculateLaborableDays(plan.GetEndDate().AddDate(0, 0, 1), realEndDate, feastDays))
+			realProgressDays = float64(calendar.laborableDays(plan.GetEndDate().AddDate(0, 0, 1), realEndDate))
 		} else if dateutil.IsEqual(realEndDate, plan.GetEndDate()) {
 			// Si la última fecha de resolución coincide con la fecha de fin de proyecto no hay retraso ni adelanto
 			realProgressDays = 0.0
 		} else {
 			// Calcula los días que van desde la fecha de resolución de la última tarea gasta la fecha final
 			// y serán días de adelanto (por eso se multiplica por -1)
-			realProgressDays = float64(CalculateLaborableDays(realEndDate, plan.GetEndDate(), feastDays)) * -1
+			realProgressDays = float64(calendar.laborableDays(realEndDate, plan.GetEndDate())) * -1
 		}
 
 		// Redondea a 1 decimal
@@ -163,12 +268,15 @@ func CalculateProgressDays(plan ProjectPlan, reviewDate time.Time) {
 		}
 
 		// Fórmula (duración esperada - duración real) * las jornadas laborales del plan / duración total del plan
-		realProgressDays = ((expectedCompleteDuration - realCompleteDuration) * workDays) / float64(plan.GetTotalDuration())
+		// Si el plan solo tiene hitos no hay duración, solo cuentan los días que se haya pasado de la fecha de fin
+		if plan.GetTotalDuration() > 0 {
+			realProgressDays = ((expectedCompleteDuration - realCompleteDuration) * workDays) / float64(plan.GetTotalDuration())
+		}
 
 		// Si la fecha de revisión -1  es > que la fecha de fin del plan, calcula los días que hay desde la fecha de finalización hasta la fecha de revisión -1
 		// y se los suma a los días
 		if dateutil.IsGt(reviewDate.AddDate(0, 0, -1), plan.GetEndDate()) {
-			realProgressDays += float64(CalculateLaborableDays(plan.GetEndDate().AddDate(0, 0, 1), reviewDate.AddDate(0, 0, -1), feastDays))
+			realProgressDays += float64(calendar.laborableDays(plan.GetEndDate().AddDate(0, 0, 1), reviewDate.AddDate(0, 0, -1)))
 		}
 
 		// Redondea a 1 decimal
@@ -178,7 +286,7 @@ func CalculateProgressDays(plan ProjectPlan, reviewDate time.Time) {
 		// Redondea a la alta los días de retraso y a la baja los de adelanto de manera que si es -1.2 será -1 y si es 1.2 será 2.
 		// Es decir 1.3 días de adelanto para gplan será un día de adelanto y 1.3 días de retraso serán 2 días
 		plan.SetEstimatedEndDate(
-			CalculateLaborableDate(plan.GetEndDate(), int(math.Ceil(plan.GetRealProgressDays())), feastDays))
+			calendar.laborableDate(plan.GetEndDate(), int(math.Ceil(plan.GetRealProgressDays()))))
 	}
 
 }
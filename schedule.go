@@ -0,0 +1,185 @@
+package gplan
+
+import "time"
+
+// Assignment datos de la planificación de una tarea
+type Assignment struct {
+	// ID de la tarea planificada
+	TaskID TaskID
+	// Recurso asignado, si la tarea necesita varios recursos es el primero de ellos
+	ResourceID ResourceID
+	// Todos los recursos asignados
+	ResourceIDs []ResourceID
+	// Trabajo de cada recurso asignado en la tarea
+	Efforts []Effort
+	// Fecha planificada de comienzo de la tarea
+	StartDate time.Time
+	// Fecha planificada de fin de la tarea
+	EndDate time.Time
+	// Indica si la tarea es un hito, en ese caso no tiene recurso y las fechas de comienzo y fin son la fecha del hito
+	Milestone bool
+	// Tramos en los que se ha dividido la tarea, vacía si la tarea no es divisible
+	Segments []Segment
+}
+
+// Segment tramo de una tarea divisible que realiza un recurso sin interrupciones
+type Segment struct {
+	// Recurso asignado al tramo
+	ResourceID ResourceID
+	// Fecha de comienzo del tramo
+	StartDate time.Time
+	// Fecha de fin del tramo
+	EndDate time.Time
+	// Jornadas estándar de trabajo del recurso en el tramo
+	Days float64
+}
+
+// Effort trabajo de un recurso en una tarea
+type Effort struct {
+	// Recurso asignado
+	ResourceID ResourceID
+	// Fecha en la que el recurso empieza a trabajar en la tarea
+	StartDate time.Time
+	// Fecha en la que el recurso termina de trabajar en la tarea
+	EndDate time.Time
+	// Jornadas estándar de trabajo del recurso en la tarea
+	Days float64
+}
+
+// clone devuelve una copia de la planificación de una tarea que no comparte las listas de recursos y trabajos
+func (a Assignment) clone() Assignment {
+	a.ResourceIDs = append([]ResourceID(nil), a.ResourceIDs...)
+	a.Efforts = append([]Effort(nil), a.Efforts...)
+	a.Segments = append([]Segment(nil), a.Segments...)
+	return a
+}
+
+// Schedule resultado de una planificación calculada con PlanSchedule. No se puede modificar una vez creado, los métodos
+// que devuelven listas devuelven copias.
+type Schedule struct {
+	startDate     time.Time
+	endDate       time.Time
+	workdays      uint
+	totalDuration uint
+	assignments   []Assignment
+	timelines     map[ResourceID]resourceTimeline
+	warnings      []*Error
+}
+
+// GetStartDate fecha de comienzo de la planificación, la menor fecha de comienzo de sus tareas
+func (s *Schedule) GetStartDate() time.Time {
+	return s.startDate
+}
+
+// GetEndDate fecha de fin de la planificación, la mayor fecha de fin de sus tareas
+func (s *Schedule) GetEndDate() time.Time {
+	return s.endDate
+}
+
+// GetWorkdays jornadas de trabajo que tiene la planificación
+func (s *Schedule) GetWorkdays() uint {
+	return s.workdays
+}
+
+// GetTotalDuration suma de las duraciones de las tareas
+func (s *Schedule) GetTotalDuration() uint {
+	return s.totalDuration
+}
+
+// GetTotalTasks número de tareas planificadas
+func (s *Schedule) GetTotalTasks() uint {
+	return uint(len(s.assignments))
+}
+
+// GetAssignments planificación de cada tarea en el orden en que se han planificado
+func (s *Schedule) GetAssignments() []Assignment {
+	var assignments = make([]Assignment, 0, len(s.assignments))
+	for _, assignment := range s.assignments {
+		assignments = append(assignments, assignment.clone())
+	}
+	return assignments
+}
+
+// GetAssignment planificación de una tarea, devuelve false si la tarea no está en la planificación
+func (s *Schedule) GetAssignment(taskID TaskID) (Assignment, bool) {
+	for _, assignment := range s.assignments {
+		if assignment.TaskID == taskID {
+			return assignment.clone(), true
+		}
+	}
+	return Assignment{}, false
+}
+
+// GetResourceTimeline agenda de un recurso, los intervalos ocupados por sus tareas ordenados por fecha de comienzo
+func (s *Schedule) GetResourceTimeline(resourceID ResourceID) []Booking {
+	return append([]Booking{}, s.timelines[resourceID]...)
+}
+
+// GetWarnings avisos que no han impedido realizar la planificación
+func (s *Schedule) GetWarnings() []*Error {
+	return append([]*Error{}, s.warnings...)
+}
+
+// Apply copia la planificación en las tareas, recursos y el propio plan para mantener el comportamiento de Planning
+func (s *Schedule) Apply(plan ProjectPlan) {
+
+	var (
+		nextAvailableDates = make(map[ResourceID]time.Time)
+		assignmentsIndex   = make(map[TaskID]*Assignment, len(s.assignments))
+	)
+
+	// Índice de la planificación de cada tarea para no recorrer todas las asignaciones por cada tarea
+	for i := range s.assignments {
+		if _, exist := assignmentsIndex[s.assignments[i].TaskID]; !exist {
+			assignmentsIndex[s.assignments[i].TaskID] = &s.assignments[i]
+		}
+	}
+
+	for _, task := range plan.GetTasks() {
+		indexed, exist := assignmentsIndex[task.GetID()]
+		if !exist {
+			continue
+		}
+
+		// Las tareas reciben una copia para que no compartan las listas con la planificación
+		assignment := indexed.clone()
+
+		task.SetStartDate(assignment.StartDate)
+		task.SetEndDate(assignment.EndDate)
+
+		// Los hitos no tienen recurso
+		if assignment.Milestone {
+			task.SetResourceID(nil)
+			task.SetResourceIDs(nil)
+			task.SetSegments(nil)
+			task.SetEfforts(nil)
+			continue
+		}
+
+		resourceID := assignment.ResourceID
+		task.SetResourceID(&resourceID)
+		task.SetResourceIDs(assignment.ResourceIDs)
+		task.SetSegments(assignment.Segments)
+		task.SetEfforts(assignment.Efforts)
+
+		// La siguiente fecha de disponibilidad de los recursos es el día siguiente a la última tarea que tienen asignada
+		for _, effort := range assignment.Efforts {
+			nextAvailableDate := effort.EndDate.AddDate(0, 0, 1)
+			if nextAvailableDate.After(nextAvailableDates[effort.ResourceID]) {
+				nextAvailableDates[effort.ResourceID] = nextAvailableDate
+			}
+		}
+	}
+
+	for _, resource := range plan.GetResources() {
+		if nextAvailableDate, exist := nextAvailableDates[resource.GetID()]; exist {
+			resource.SetNextAvailableDate(nextAvailableDate)
+		}
+	}
+
+	plan.SetStartDate(s.startDate)
+	plan.SetEndDate(s.endDate)
+	plan.SetWorkdays(s.workdays)
+	plan.SetTotalTasks(s.GetTotalTasks())
+	plan.SetTotalDuration(s.totalDuration)
+}
@@ -0,0 +1,135 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PlanSchedule", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-10"), []*Holidays{
+				NewHolidays(parseDate("2021-06-17"), parseDate("2021-06-18")),
+			}),
+			NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-05-07"), nil),
+			NewResource("David.Attrache", "David Attrache", "maquetacion", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "maquetacion", parseDate("2021-06-07"), nil),
+		}
+
+		feastDays := []*Holidays{
+			NewHolidays(parseDate("2021-06-10"), parseDate("2021-06-11")),
+			NewHolidays(parseDate("2021-07-21"), parseDate("2021-07-22")),
+		}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 30, 10),
+			NewTask("Tarea2", "Summary", "maquetacion", 50, 2),
+			NewTask("Tarea3", "Summary", "backend", 40, 1),
+			NewTask("Tarea4", "Summary", "maquetacion", 20, 20),
+			NewTask("Tarea5", "Summary", "backend", 60, 7),
+			NewTask("Tarea6", "Summary", "backend", 10, 9),
+		}
+
+		BlocksTo(tasks[3], tasks[0])
+		BlocksTo(tasks[5], tasks[2])
+		BlocksTo(tasks[5], tasks[1])
+
+		plan = NewProjectPlan("test-plan", tasks, resources, feastDays)
+	})
+
+	When("calculamos la planificación sin aplicarla", func() {
+
+		It("No debe modificar el plan y debe devolver la planificación", func() {
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			// El plan no se modifica
+			Expect(plan.Tasks[0].ID).Should(BeEquivalentTo("Tarea1"))
+			for _, task := range plan.Tasks {
+				Expect(task.ResourceID).Should(BeNil())
+				Expect(task.StartDate).Should(BeZero())
+			}
+			Expect(plan.StartDate).Should(BeZero())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea6 2021-06-07 2021-06-21 cslopez",
+				"Tarea4 2021-06-07 2021-07-06 David.Attrache",
+				"Tarea1 2021-07-07 2021-07-20 ahg",
+				"Tarea3 2021-06-22 2021-06-22 cslopez",
+				"Tarea2 2021-06-22 2021-06-23 Noemi",
+				"Tarea5 2021-06-23 2021-07-01 cslopez",
+			}))
+			Expect(schedule.GetStartDate().Format("2006-01-02")).Should(Equal("2021-06-07"))
+			Expect(schedule.GetEndDate().Format("2006-01-02")).Should(Equal("2021-07-20"))
+			Expect(schedule.GetWorkdays()).Should(BeEquivalentTo(30))
+			Expect(schedule.GetTotalTasks()).Should(BeEquivalentTo(6))
+			Expect(schedule.GetTotalDuration()).Should(BeEquivalentTo(49))
+		})
+
+		It("Debe devolver la misma planificación si se calcula dos veces", func() {
+			first, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+			second, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(second)).Should(Equal(compareAssignments(first)))
+		})
+	})
+
+	When("aplicamos la planificación al plan", func() {
+
+		It("Debe dejar el plan igual que Planning", func() {
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			schedule.Apply(plan)
+
+			comparePlan(plan.Tasks, []string{
+				"2021-07-07 2021-07-20 ahg",
+				"2021-06-22 2021-06-23 Noemi",
+				"2021-06-22 2021-06-22 cslopez",
+				"2021-06-07 2021-07-06 David.Attrache",
+				"2021-06-23 2021-07-01 cslopez",
+				"2021-06-07 2021-06-21 cslopez",
+			})
+			Expect(plan.Resources[1].GetNextAvailableDate().Format("2006-01-02")).Should(Equal("2021-07-02"))
+			Expect(plan.StartDate.Format("2006-01-02")).Should(Equal("2021-06-07"))
+			Expect(plan.EndDate.Format("2006-01-02")).Should(Equal("2021-07-20"))
+			Expect(plan.Workdays).Should(BeEquivalentTo(30))
+			Expect(plan.TotalTasks).Should(BeEquivalentTo(6))
+			Expect(plan.TotalDuration).Should(BeEquivalentTo(49))
+		})
+	})
+
+	When("la planificación falla", func() {
+
+		It("No debe modificar el plan", func() {
+			plan.Tasks[0].ResourceType = "no-existe"
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(schedule).Should(BeNil())
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("no hay recursos para los tipos de estas tareas")))
+
+			for _, task := range plan.Tasks {
+				Expect(task.ResourceID).Should(BeNil())
+			}
+		})
+	})
+
+})
+
+func compareAssignments(schedule *gplan.Schedule) []string {
+	var result []string
+	for _, assignment := range schedule.GetAssignments() {
+		result = append(result, fmt.Sprintf("%s %s %s %s", assignment.TaskID, assignment.StartDate.Format("2006-01-02"),
+			assignment.EndDate.Format("2006-01-02"), assignment.ResourceID))
+	}
+	return result
+}
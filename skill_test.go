@@ -0,0 +1,75 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Skill", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "maquetacion", parseDate("2021-06-07"), nil),
+			NewResource("fullstack", "Full Stack", "backend", parseDate("2021-06-07"), nil),
+		}
+
+		// Puede hacer tareas de maquetación pero tarda el doble
+		resources[2].Skills = []*Skill{NewSkill("maquetacion", 50)}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 5),
+			NewTask("Tarea2", "Summary", "maquetacion", 20, 5),
+			NewTask("Tarea3", "Summary", "maquetacion", 30, 2),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("un recurso tiene varias habilidades", func() {
+
+		It("Debe poder asignársele tareas de cualquiera de ellas según su rendimiento", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			// Tarea3 con fullstack dura 4 días y termina antes que esperando a Noemi
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-11 ahg",
+				"2021-06-07 2021-06-11 Noemi",
+				"2021-06-07 2021-06-10 fullstack",
+			})
+		})
+
+		It("Su rendimiento debe tenerse en cuenta en el avance esperado", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			err = gplan.Review(plan, parseDate("2021-06-09"))
+			Expect(err).Should(BeNil())
+
+			// Lleva dos días de cuatro
+			Expect(plan.Tasks[2].ExpectedProgress).Should(BeEquivalentTo(50))
+		})
+	})
+
+	When("un recurso no puede realizar ninguna tarea", func() {
+
+		It("Debe planificar y devolver un aviso con todos sus tipos", func() {
+			plan.Resources = append(plan.Resources,
+				NewResource("tester", "Tester", "qa", parseDate("2021-06-07"), nil))
+			plan.Resources[3].Skills = []*Skill{NewSkill("devops", 0)}
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(schedule.GetWarnings()).Should(HaveLen(1))
+			Expect(schedule.GetWarnings()[0].Message).Should(Equal(fmt.Errorf("no existen tareas para el recurso tester de tipo devops, qa")))
+		})
+	})
+
+})
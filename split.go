@@ -0,0 +1,209 @@
+package gplan
+
+import (
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// defaultMinSplitGap días laborables seguidos sin trabajar a partir de los cuales se interrumpe una tarea divisible si
+// el plan no indica otro valor
+const defaultMinSplitGap = 5
+
+// taskSegment tramo de una tarea divisible calculado durante la planificación
+type taskSegment struct {
+	resource  Resource
+	startDate time.Time
+	endDate   time.Time
+	// Trabajo realizado en el tramo en porcentaje de jornadas
+	work uint
+}
+
+// splitScheduledTask planifica una tarea divisible por tramos. Cada tramo lo realiza un solo recurso y termina cuando
+// el recurso va a dejar de trabajar en ella durante al menos minSplitGap días laborables del plan, porque tiene una
+// ausencia larga o deja de estar disponible, o en modo BackfillScheduling cuando llega a una tarea ya planificada en su
+// agenda. Lo que queda de la tarea lo continúa el recurso que antes pueda empezar a trabajar en ella. Si minEndDate no
+// está vacía retrasa el comienzo de la tarea hasta que su fecha de fin sea igual o superior a ella
+func (p *planner) splitScheduledTask(task Task, startDate time.Time, minEndDate time.Time) (*scheduledTaskInfo, *Error) {
+
+	var resources = p.eligibleResources(task, task.GetResourceType(), nil)
+
+	if len(resources) == 0 {
+		return nil, newError(ErrNotEnoughResources, "no hay suficientes recursos para las siguientes tareas", []TaskID{task.GetID()})
+	}
+
+	var segments []taskSegment
+
+	for {
+		var complete bool
+		segments, complete = p.fitSegments(task, resources, startDate)
+		if !complete {
+			return nil, newError(ErrUnschedulable, "las siguientes tareas no se pueden terminar antes de que sus recursos dejen de estar disponibles",
+				[]TaskID{task.GetID()})
+		}
+
+		// Si termina antes de lo que le permiten sus dependencias se retrasa el comienzo un día
+		if minEndDate.IsZero() || dateutil.IsGte(segments[len(segments)-1].endDate, minEndDate) {
+			break
+		}
+		startDate = segments[0].startDate.AddDate(0, 0, 1)
+	}
+
+	var (
+		info = &scheduledTaskInfo{
+			Resource:  segments[0].resource,
+			StartDate: segments[0].startDate,
+			EndDate:   segments[len(segments)-1].endDate,
+		}
+		positions = make(map[ResourceID]int)
+	)
+
+	// Cada recurso del equipo trabaja en la tarea desde su primer tramo hasta su último tramo
+	for _, segment := range segments {
+		id := segment.resource.GetID()
+		i, exist := positions[id]
+		if !exist {
+			i = len(info.Team)
+			positions[id] = i
+			info.Team = append(info.Team, segment.resource)
+			info.StartDates = append(info.StartDates, segment.startDate)
+			info.EndDates = append(info.EndDates, segment.endDate)
+			info.Efforts = append(info.Efforts, 0)
+		}
+		info.EndDates[i] = segment.endDate
+		info.Efforts[i] += segment.work
+
+		info.Segments = append(info.Segments, Segment{
+			ResourceID: id,
+			StartDate:  segment.startDate,
+			EndDate:    segment.endDate,
+			Days:       float64(segment.work) / 100,
+		})
+	}
+
+	return info, nil
+}
+
+// fitSegments calcula los tramos de una tarea divisible a partir de una fecha. Para cada tramo elige el recurso que
+// antes puede empezar a trabajar en la tarea, si empiezan a la vez prefiere el recurso del tramo anterior, después los
+// recursos preferidos de la tarea y después el que más trabajo hace en el tramo. Devuelve False si la tarea no se
+// puede terminar porque ningún recurso puede continuarla
+func (p *planner) fitSegments(task Task, resources []Resource, startDate time.Time) ([]taskSegment, bool) {
+
+	var (
+		segments    []taskSegment
+		pendingWork = task.GetDuration() * 100
+		date        = startDate
+	)
+
+	for pendingWork > 0 {
+		var best *taskSegment
+
+		for _, resource := range resources {
+			segment, ok := p.fitSegment(task, resource, date, pendingWork)
+			if !ok {
+				continue
+			}
+			if best == nil || p.isBetterSegment(task, segments, segment, *best) {
+				best = &segment
+			}
+		}
+
+		if best == nil {
+			return nil, false
+		}
+
+		segments = append(segments, *best)
+		pendingWork -= best.work
+		date = best.endDate.AddDate(0, 0, 1)
+	}
+
+	return segments, true
+}
+
+// isBetterSegment devuelve True si un tramo es mejor opción que otro para continuar una tarea divisible
+func (p *planner) isBetterSegment(task Task, previous []taskSegment, segment taskSegment, best taskSegment) bool {
+
+	if !dateutil.IsEqual(segment.startDate, best.startDate) {
+		return dateutil.IsLt(segment.startDate, best.startDate)
+	}
+
+	if len(previous) > 0 {
+		last := previous[len(previous)-1].resource.GetID()
+		if (segment.resource.GetID() == last) != (best.resource.GetID() == last) {
+			return segment.resource.GetID() == last
+		}
+	}
+
+	preferred := task.GetPreferredResourceIDs()
+	if containsResourceID(preferred, segment.resource.GetID()) != containsResourceID(preferred, best.resource.GetID()) {
+		return containsResourceID(preferred, segment.resource.GetID())
+	}
+
+	return segment.work > best.work
+}
+
+// fitSegment calcula el tramo que puede realizar un recurso de una tarea divisible a partir de una fecha, hasta
+// completar el trabajo pendiente o hasta que tenga que interrumpirla. Devuelve False si el recurso deja de estar
+// disponible antes de poder empezar
+func (p *planner) fitSegment(task Task, resource Resource, startDate time.Time, pendingWork uint) (taskSegment, bool) {
+
+	var (
+		id       = resource.GetID()
+		profile  = p.resourceProfiles[id]
+		timeline = p.timelines[id]
+		date     = startDate
+	)
+
+	nextAvailableDate := p.nextAvailableDates[id]
+	if p.schedulingMode == BackfillScheduling {
+		nextAvailableDate = p.availableFrom[id]
+	}
+	if dateutil.IsGt(nextAvailableDate, date) {
+		date = nextAvailableDate
+	}
+
+	// Busca el primer día en el que el recurso trabaja y no tiene otra tarea
+	for {
+		if profile.isGone(date) {
+			return taskSegment{}, false
+		}
+		if booking := timeline.overlap(date, date); booking != nil {
+			date = booking.EndDate.AddDate(0, 0, 1)
+			continue
+		}
+		if profile.taskCapacity(date, task.GetResourceType()) > 0 {
+			break
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+
+	var (
+		segment = taskSegment{resource: resource, startDate: date, endDate: date}
+		idle    uint
+	)
+
+	for day := date; !profile.isGone(day) && timeline.overlap(day, day) == nil; day = day.AddDate(0, 0, 1) {
+
+		if capacity := profile.taskCapacity(day, task.GetResourceType()); capacity > 0 {
+			segment.endDate = day
+			idle = 0
+			if segment.work+capacity >= pendingWork {
+				segment.work = pendingWork
+				break
+			}
+			segment.work += capacity
+			continue
+		}
+
+		// Los días laborables del plan en los que el recurso no trabaja cuentan como ausencia
+		if p.calendar.isLaborableDay(day) {
+			idle++
+			if idle >= p.minSplitGap {
+				break
+			}
+		}
+	}
+
+	return segment, true
+}
@@ -0,0 +1,132 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func compareSegments(task *Task) []string {
+	var result []string
+	for _, segment := range task.Segments {
+		result = append(result, fmt.Sprintf("%s %s %s %.1f", segment.ResourceID, segment.StartDate.Format("2006-01-02"),
+			segment.EndDate.Format("2006-01-02"), segment.Days))
+	}
+	return result
+}
+
+var _ = Describe("Split", func() {
+
+	When("el recurso de una tarea divisible tiene una ausencia larga", func() {
+
+		var plan *ProjectPlan
+
+		BeforeEach(func() {
+			resources := []*Resource{
+				NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+				NewResource("jmartin", "Juan Martín", "backend", parseDate("2021-06-16"), nil),
+			}
+			resources[0].Unavailabilities = []*Unavailability{
+				NewUnavailability(parseDate("2021-06-14"), parseDate("2021-06-25"), "Baja"),
+			}
+
+			tasks := []*Task{
+				NewTask("Tarea1", "Summary", "backend", 10, 8),
+			}
+			tasks[0].Splittable = true
+
+			plan = NewProjectPlan("test-plan", tasks, resources, nil)
+		})
+
+		It("Otro recurso debe terminar lo que queda de la tarea", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-18 ahg",
+			})
+			Expect(plan.Tasks[0].ResourceIDs).Should(Equal([]gplan.ResourceID{"ahg", "jmartin"}))
+			Expect(compareSegments(plan.Tasks[0])).Should(Equal([]string{
+				"ahg 2021-06-07 2021-06-11 5.0",
+				"jmartin 2021-06-16 2021-06-18 3.0",
+			}))
+		})
+
+		It("El progreso esperado debe contar solo el trabajo de los tramos", func() {
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			err = gplan.Review(plan, parseDate("2021-06-17"))
+			Expect(err).Should(BeNil())
+			Expect(plan.Tasks[0].ExpectedCompleteDuration).Should(Equal(uint(6)))
+			Expect(plan.Tasks[0].ExpectedProgress).Should(Equal(uint(75)))
+		})
+
+		It("Si no es divisible el recurso debe hacerla entera", func() {
+			plan.Tasks[0].Splittable = false
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-16 2021-06-25 jmartin",
+			})
+			Expect(plan.Tasks[0].Segments).Should(BeEmpty())
+		})
+	})
+
+	When("una tarea divisible se planifica en modo BackfillScheduling", func() {
+
+		It("Debe interrumpirse para dejar pasar a las tareas ya planificadas", func() {
+			resources := []*Resource{
+				NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			}
+
+			tasks := []*Task{
+				NewTask("Tarea1", "Summary", "backend", 10, 3),
+				NewTask("Tarea2", "Summary", "backend", 20, 4),
+			}
+			tasks[0].SetConstraint(gplan.MustStartOn, parseDate("2021-06-09"))
+			tasks[1].Splittable = true
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			plan.SchedulingMode = gplan.BackfillScheduling
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-09 2021-06-11 ahg",
+				"2021-06-07 2021-06-15 ahg",
+			})
+			Expect(compareSegments(plan.Tasks[1])).Should(Equal([]string{
+				"ahg 2021-06-07 2021-06-08 2.0",
+				"ahg 2021-06-14 2021-06-15 2.0",
+			}))
+		})
+	})
+
+	When("una tarea divisible reparte su duración entre varios recursos", func() {
+
+		It("Debe devolver un error", func() {
+			resources := []*Resource{
+				NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			}
+
+			tasks := []*Task{
+				NewTask("Tarea1", "Summary", "backend", 10, 4),
+			}
+			tasks[0].Splittable = true
+			tasks[0].MaxTeamSize = 2
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas son divisibles y necesitan varios recursos")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+		})
+	})
+
+})
@@ -0,0 +1,268 @@
+package gplan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// StaffingRequirement recursos adicionales de un tipo que necesita un plan para terminar en una fecha
+type StaffingRequirement struct {
+	// Tipo de los recursos adicionales
+	ResourceType string
+	// Número de recursos adicionales
+	Count uint
+	// Fecha en la que tiene que empezar a trabajar cada uno de los recursos adicionales
+	StartDates []time.Time
+}
+
+// MinimumTeam resultado del cálculo del equipo mínimo para terminar un plan en una fecha
+type MinimumTeam struct {
+	// Recursos adicionales por tipo ordenados por tipo, solo los tipos de los que hace falta alguno
+	Requirements []StaffingRequirement
+	// Planificación del plan con los recursos adicionales
+	Schedule *Schedule
+}
+
+// CalculateMinimumTeam calcula cuántos recursos adicionales de cada tipo necesita un plan para terminar como tarde en
+// una fecha objetivo y cuándo tienen que empezar a trabajar. Planifica el plan con PlanSchedule añadiendo recursos
+// virtuales de los tipos que necesitan sus tareas, que trabajan con el calendario del plan desde startDate, primero
+// de uno en uno añadiendo cada vez el del tipo con el que antes termina el plan y después quitando los que no hacen
+// falta. Los recursos virtuales tienen como ID el tipo seguido de + y su número, por ejemplo backend+1.
+// Si el plan no puede terminar en la fecha objetivo ni con un recurso adicional por cada tarea devuelve un error con
+// las tareas que terminan después de ella, ya que lo impiden las dependencias entre tareas
+func CalculateMinimumTeam(startDate time.Time, plan ProjectPlan, targetEndDate time.Time) (*MinimumTeam, *Error) {
+
+	// Convierte a local las fechas
+	startDate = startDate.Local()
+	targetEndDate = targetEndDate.Local()
+
+	var (
+		types  []string
+		limits = make(map[string]uint)
+	)
+
+	// El máximo de recursos adicionales de cada tipo es el que permite realizar a la vez todas las tareas que lo
+	// necesitan
+	for _, task := range plan.GetTasks() {
+		if IsMilestone(task) {
+			continue
+		}
+		for _, demand := range taskDemands(task) {
+			quantity := demand.GetQuantity()
+			if task.GetMaxTeamSize() > quantity {
+				quantity = task.GetMaxTeamSize()
+			}
+			if _, exist := limits[demand.GetResourceType()]; !exist {
+				types = append(types, demand.GetResourceType())
+			}
+			limits[demand.GetResourceType()] += quantity
+		}
+	}
+	sort.Strings(types)
+
+	planWith := func(extra map[string]uint) (*Schedule, *Error) {
+		return PlanSchedule(startDate, newStaffedPlan(plan, startDate, types, extra))
+	}
+	meetsTarget := func(schedule *Schedule, err *Error) bool {
+		return err == nil && dateutil.IsLte(schedule.GetEndDate(), targetEndDate)
+	}
+
+	// Si ni con el máximo de recursos adicionales se alcanza la fecha objetivo no es posible alcanzarla
+	schedule, err := planWith(limits)
+	if err != nil {
+		return nil, err
+	}
+	if !meetsTarget(schedule, err) {
+		var lateTasks []TaskID
+		for _, assignment := range schedule.GetAssignments() {
+			endDate := assignment.EndDate
+			if assignment.Milestone {
+				endDate = newPlanCalendar(plan).laborableDate(endDate, -1)
+			}
+			if dateutil.IsGt(endDate, targetEndDate) {
+				lateTasks = append(lateTasks, assignment.TaskID)
+			}
+		}
+		return nil, newError(ErrUnschedulable, "la fecha objetivo no se puede alcanzar, por las dependencias entre tareas el plan no puede terminar antes del %s",
+			lateTasks, schedule.GetEndDate().Format("2006-01-02"))
+	}
+
+	// Añade de uno en uno el recurso del tipo con el que antes termina el plan hasta alcanzar la fecha objetivo
+	var extra = make(map[string]uint)
+
+	schedule, err = planWith(extra)
+	for !meetsTarget(schedule, err) {
+		var (
+			bestType     string
+			bestSchedule *Schedule
+			bestErr      *Error
+		)
+		for _, resourceType := range types {
+			if extra[resourceType] == limits[resourceType] {
+				continue
+			}
+			extra[resourceType]++
+			sh, e := planWith(extra)
+			extra[resourceType]--
+
+			if bestType == "" || (bestErr != nil && e == nil) ||
+				(e == nil && bestErr == nil && dateutil.IsLt(sh.GetEndDate(), bestSchedule.GetEndDate())) {
+				bestType, bestSchedule, bestErr = resourceType, sh, e
+			}
+		}
+		extra[bestType]++
+		schedule, err = bestSchedule, bestErr
+	}
+
+	// Quita los recursos adicionales que no hacen falta para alcanzar la fecha objetivo
+	for _, resourceType := range types {
+		for extra[resourceType] > 0 {
+			extra[resourceType]--
+			sh, e := planWith(extra)
+			if !meetsTarget(sh, e) {
+				extra[resourceType]++
+				break
+			}
+			schedule = sh
+		}
+	}
+
+	var team = &MinimumTeam{Schedule: schedule}
+
+	for _, resourceType := range types {
+		if extra[resourceType] == 0 {
+			continue
+		}
+		requirement := StaffingRequirement{ResourceType: resourceType, Count: extra[resourceType]}
+		for n := uint(1); n <= extra[resourceType]; n++ {
+			requirement.StartDates = append(requirement.StartDates,
+				firstWorkDate(schedule, virtualResourceID(resourceType, n)))
+		}
+		team.Requirements = append(team.Requirements, requirement)
+	}
+
+	return team, nil
+}
+
+// firstWorkDate devuelve la fecha en la que un recurso empieza a trabajar en una planificación, vacía si no tiene
+// ninguna tarea
+func firstWorkDate(schedule *Schedule, resourceID ResourceID) time.Time {
+	var date time.Time
+	for _, assignment := range schedule.GetAssignments() {
+		for _, effort := range assignment.Efforts {
+			if effort.ResourceID == resourceID && (date.IsZero() || dateutil.IsLt(effort.StartDate, date)) {
+				date = effort.StartDate
+			}
+		}
+	}
+	return date
+}
+
+// virtualResourceID devuelve el ID del recurso virtual adicional número n de un tipo
+func virtualResourceID(resourceType string, n uint) ResourceID {
+	return ResourceID(fmt.Sprintf("%s+%d", resourceType, n))
+}
+
+// staffedPlan plan con recursos virtuales adicionales
+type staffedPlan struct {
+	ProjectPlan
+	resources []Resource
+}
+
+// newStaffedPlan crea un plan con los recursos de un plan más los recursos virtuales adicionales de cada tipo
+func newStaffedPlan(plan ProjectPlan, startDate time.Time, types []string, extra map[string]uint) *staffedPlan {
+
+	var p = &staffedPlan{
+		ProjectPlan: plan,
+		resources:   append([]Resource{}, plan.GetResources()...),
+	}
+
+	for _, resourceType := range types {
+		for n := uint(1); n <= extra[resourceType]; n++ {
+			p.resources = append(p.resources, &virtualResource{
+				id:                virtualResourceID(resourceType, n),
+				resourceType:      resourceType,
+				availableFrom:     startDate,
+				nextAvailableDate: startDate,
+			})
+		}
+	}
+
+	return p
+}
+
+func (p *staffedPlan) GetResources() []Resource {
+	return p.resources
+}
+
+// virtualResource recurso virtual que se añade a un plan para calcular su equipo mínimo. Trabaja a jornada completa
+// con el calendario del plan
+type virtualResource struct {
+	id                ResourceID
+	resourceType      string
+	availableFrom     time.Time
+	nextAvailableDate time.Time
+}
+
+func (r *virtualResource) GetID() ResourceID {
+	return r.id
+}
+
+func (r *virtualResource) GetDescription() string {
+	return fmt.Sprintf("Recurso adicional de tipo %s", r.resourceType)
+}
+
+func (r *virtualResource) GetType() string {
+	return r.resourceType
+}
+
+func (r *virtualResource) GetSkills() []Skill {
+	return nil
+}
+
+func (r *virtualResource) GetEfficiency() uint {
+	return 0
+}
+
+func (r *virtualResource) GetAvailableFrom() time.Time {
+	return r.availableFrom
+}
+
+func (r *virtualResource) SetAvailableFrom(t time.Time) {
+	r.availableFrom = t
+}
+
+func (r *virtualResource) GetAvailableUntil() time.Time {
+	return time.Time{}
+}
+
+func (r *virtualResource) GetNextAvailableDate() time.Time {
+	return r.nextAvailableDate
+}
+
+func (r *virtualResource) SetNextAvailableDate(t time.Time) {
+	r.nextAvailableDate = t
+}
+
+func (r *virtualResource) GetHolidays() []Holidays {
+	return nil
+}
+
+func (r *virtualResource) GetUnavailabilities() []Unavailability {
+	return nil
+}
+
+func (r *virtualResource) GetCalendar() Calendar {
+	return nil
+}
+
+func (r *virtualResource) GetAllocations() []Allocation {
+	return nil
+}
+
+func (r *virtualResource) GetCost() float64 {
+	return 0
+}
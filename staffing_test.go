@@ -0,0 +1,69 @@
+package gplan_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Staffing", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 5),
+			NewTask("Tarea2", "Summary", "backend", 20, 5),
+			NewTask("Tarea3", "Summary", "backend", 30, 5),
+			NewTask("QA", "Summary", "qa", 40, 2),
+		}
+
+		BlocksTo(tasks[0], tasks[3])
+		BlocksTo(tasks[1], tasks[3])
+		BlocksTo(tasks[2], tasks[3])
+
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("calculamos el equipo mínimo para terminar en una fecha", func() {
+
+		It("Debe devolver los recursos adicionales de cada tipo y cuándo empiezan", func() {
+			team, err := gplan.CalculateMinimumTeam(parseDate("2021-06-07"), plan, parseDate("2021-06-15"))
+			Expect(err).Should(BeNil())
+
+			Expect(team.Requirements).Should(HaveLen(1))
+			Expect(team.Requirements[0].ResourceType).Should(Equal("backend"))
+			Expect(team.Requirements[0].Count).Should(BeEquivalentTo(2))
+			Expect(team.Requirements[0].StartDates).Should(Equal([]time.Time{parseDate("2021-06-07"), parseDate("2021-06-07")}))
+			Expect(team.Schedule.GetEndDate().Format("2006-01-02")).Should(Equal("2021-06-15"))
+
+			// No debe modificar el plan
+			Expect(plan.Resources).Should(HaveLen(2))
+		})
+
+		It("Si el plan ya termina a tiempo no necesita recursos adicionales", func() {
+			team, err := gplan.CalculateMinimumTeam(parseDate("2021-06-07"), plan, parseDate("2021-06-30"))
+			Expect(err).Should(BeNil())
+			Expect(team.Requirements).Should(BeEmpty())
+		})
+	})
+
+	When("las dependencias impiden terminar en la fecha", func() {
+
+		It("Debe devolver un error con las tareas que terminan tarde", func() {
+			_, err := gplan.CalculateMinimumTeam(parseDate("2021-06-07"), plan, parseDate("2021-06-11"))
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("la fecha objetivo no se puede alcanzar, por las dependencias entre tareas el plan no puede terminar antes del 2021-06-15")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"QA"}))
+		})
+	})
+
+})
@@ -0,0 +1,108 @@
+package gplan
+
+import (
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// Candidate planificación posible de una tarea con uno de los recursos disponibles
+type Candidate struct {
+	// Recurso con el que se ha calculado la planificación
+	ResourceID ResourceID
+	// Fecha de comienzo de la tarea con el recurso
+	StartDate time.Time
+	// Fecha de fin de la tarea con el recurso
+	EndDate time.Time
+	// Jornadas de trabajo que tiene ya asignadas el recurso, suma de las duraciones de sus tareas
+	AssignedWorkdays uint
+	// Indica si el recurso tiene asignada alguna de las tareas que bloquean a la tarea
+	Predecessor bool
+	// Coste de una jornada de trabajo del recurso
+	Cost float64
+}
+
+// AssignmentStrategy interface a implementar para elegir el recurso al que se asigna una tarea
+type AssignmentStrategy interface {
+	// Choose devuelve la posición en candidates del candidato elegido. Los candidatos vienen en el orden de los
+	// recursos del plan y siempre hay al menos uno
+	Choose(task Task, candidates []Candidate) int
+}
+
+// EarliestFinishStrategy elige el recurso con el que la tarea termina antes. Es la estrategia por defecto
+type EarliestFinishStrategy struct{}
+
+// Choose implementa AssignmentStrategy
+func (EarliestFinishStrategy) Choose(task Task, candidates []Candidate) int {
+	return chooseBest(candidates, func(a, b Candidate) int { return 0 })
+}
+
+// LoadBalancingStrategy elige el recurso con menos jornadas asignadas para repartir el trabajo
+type LoadBalancingStrategy struct{}
+
+// Choose implementa AssignmentStrategy
+func (LoadBalancingStrategy) Choose(task Task, candidates []Candidate) int {
+	return chooseBest(candidates, func(a, b Candidate) int {
+		return compareUint(a.AssignedWorkdays, b.AssignedWorkdays)
+	})
+}
+
+// ContinuityStrategy elige un recurso que tenga asignada alguna de las tareas que bloquean a la tarea, para que el
+// trabajo lo continúe quien lo empezó
+type ContinuityStrategy struct{}
+
+// Choose implementa AssignmentStrategy
+func (ContinuityStrategy) Choose(task Task, candidates []Candidate) int {
+	return chooseBest(candidates, func(a, b Candidate) int {
+		switch {
+		case a.Predecessor && !b.Predecessor:
+			return -1
+		case !a.Predecessor && b.Predecessor:
+			return 1
+		}
+		return 0
+	})
+}
+
+// CheapestStrategy elige el recurso con menor coste por jornada
+type CheapestStrategy struct{}
+
+// Choose implementa AssignmentStrategy
+func (CheapestStrategy) Choose(task Task, candidates []Candidate) int {
+	return chooseBest(candidates, func(a, b Candidate) int {
+		switch {
+		case a.Cost < b.Cost:
+			return -1
+		case a.Cost > b.Cost:
+			return 1
+		}
+		return 0
+	})
+}
+
+// chooseBest devuelve la posición del mejor candidato según compare. Si compare los considera iguales gana el que
+// termine antes y si terminan a la vez el primero
+func chooseBest(candidates []Candidate, compare func(a, b Candidate) int) int {
+
+	var best int
+
+	for i := 1; i < len(candidates); i++ {
+		result := compare(candidates[i], candidates[best])
+		if result < 0 || (result == 0 && dateutil.IsLt(candidates[i].EndDate, candidates[best].EndDate)) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// compareUint compara dos enteros sin signo devolviendo -1, 0 o 1
+func compareUint(a uint, b uint) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,150 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// lastCandidateStrategy estrategia de prueba que elige siempre el último candidato
+type lastCandidateStrategy struct{}
+
+func (lastCandidateStrategy) Choose(task gplan.Task, candidates []gplan.Candidate) int {
+	return len(candidates) - 1
+}
+
+// invalidStrategy estrategia de prueba que elige un candidato que no existe
+type invalidStrategy struct{}
+
+func (invalidStrategy) Choose(task gplan.Task, candidates []gplan.Candidate) int {
+	return len(candidates)
+}
+
+var _ = Describe("AssignmentStrategy", func() {
+
+	var plan *ProjectPlan
+
+	When("los recursos tienen distinta disponibilidad y coste", func() {
+
+		BeforeEach(func() {
+			resources := []*Resource{
+				NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+				NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-06-09"), nil),
+			}
+			resources[0].Cost = 300
+			resources[1].Cost = 200
+
+			tasks := []*Task{
+				NewTask("Tarea1", "Summary", "backend", 10, 2),
+				NewTask("Tarea2", "Summary", "backend", 20, 2),
+			}
+
+			plan = NewProjectPlan("test-plan", tasks, resources, nil)
+		})
+
+		It("Por defecto debe elegir el recurso que termine antes", func() {
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-07 2021-06-08 ahg",
+				"Tarea2 2021-06-09 2021-06-10 ahg",
+			}))
+		})
+
+		It("Con LoadBalancingStrategy debe elegir el recurso con menos jornadas asignadas", func() {
+			plan.AssignmentStrategy = gplan.LoadBalancingStrategy{}
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-07 2021-06-08 ahg",
+				"Tarea2 2021-06-09 2021-06-10 cslopez",
+			}))
+		})
+
+		It("Con CheapestStrategy debe elegir el recurso más barato", func() {
+			plan.AssignmentStrategy = gplan.CheapestStrategy{}
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-09 2021-06-10 cslopez",
+				"Tarea2 2021-06-11 2021-06-14 cslopez",
+			}))
+		})
+
+		It("Debe poder usarse una estrategia propia", func() {
+			plan.AssignmentStrategy = lastCandidateStrategy{}
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-09 2021-06-10 cslopez",
+				"Tarea2 2021-06-11 2021-06-14 cslopez",
+			}))
+		})
+
+		It("Si la estrategia elige un candidato que no existe debe devolver un error", func() {
+			plan.AssignmentStrategy = invalidStrategy{}
+
+			_, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("la estrategia de asignación ha elegido un recurso que no existe para las siguientes tareas")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+		})
+	})
+
+	When("una tarea depende de otra asignada a un recurso ocupado", func() {
+
+		BeforeEach(func() {
+			resources := []*Resource{
+				NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+				NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-06-07"), nil),
+			}
+
+			tasks := []*Task{
+				NewTask("Tarea1", "Summary", "backend", 10, 2),
+				NewTask("Tarea2", "Summary", "backend", 20, 3),
+				NewTask("Tarea3", "Summary", "backend", 30, 5),
+				NewTask("Tarea4", "Summary", "backend", 40, 1),
+			}
+
+			BlocksTo(tasks[0], tasks[3])
+
+			plan = NewProjectPlan("test-plan", tasks, resources, nil)
+		})
+
+		It("Por defecto debe elegir el recurso que termine antes", func() {
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-07 2021-06-08 ahg",
+				"Tarea2 2021-06-07 2021-06-09 cslopez",
+				"Tarea3 2021-06-09 2021-06-15 ahg",
+				"Tarea4 2021-06-10 2021-06-10 cslopez",
+			}))
+		})
+
+		It("Con ContinuityStrategy debe elegir el recurso que hizo la tarea que la bloquea", func() {
+			plan.AssignmentStrategy = gplan.ContinuityStrategy{}
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			Expect(compareAssignments(schedule)).Should(Equal([]string{
+				"Tarea1 2021-06-07 2021-06-08 ahg",
+				"Tarea2 2021-06-07 2021-06-09 cslopez",
+				"Tarea3 2021-06-09 2021-06-15 ahg",
+				"Tarea4 2021-06-16 2021-06-16 ahg",
+			}))
+		})
+	})
+
+})
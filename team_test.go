@@ -0,0 +1,117 @@
+package gplan_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResourceDemand", func() {
+
+	var (
+		tasks     []*Task
+		resources []*Resource
+	)
+
+	BeforeEach(func() {
+		resources = []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("cslopez", "Carlos Sobrino", "backend", parseDate("2021-06-07"), nil),
+			NewResource("ops", "Operaciones", "sistemas", parseDate("2021-06-09"), nil),
+		}
+
+		tasks = []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 3),
+			NewTask("Programacion en pareja", "Summary", "backend", 20, 2),
+			NewTask("Despliegue", "Summary", "sistemas", 30, 1),
+		}
+
+		tasks[1].ResourceDemands = []*ResourceDemand{NewResourceDemand("backend", 2)}
+		tasks[2].ResourceDemands = []*ResourceDemand{
+			NewResourceDemand("sistemas", 1),
+			NewResourceDemand("backend", 1),
+		}
+	})
+
+	When("hay tareas que necesitan varios recursos a la vez", func() {
+
+		It("Deben planificarse cuando todos sus recursos estén libres", func() {
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			var result []string
+			for _, task := range plan.Tasks {
+				result = append(result, fmt.Sprintf("%s %s %v", task.StartDate.Format("2006-01-02"),
+					task.EndDate.Format("2006-01-02"), task.ResourceIDs))
+			}
+
+			// La programación en pareja espera a que ahg termine Tarea1
+			Expect(result).Should(Equal([]string{
+				"2021-06-07 2021-06-09 [ahg]",
+				"2021-06-10 2021-06-11 [cslopez ahg]",
+				"2021-06-14 2021-06-14 [ops ahg]",
+			}))
+			Expect(*plan.Tasks[1].ResourceID).Should(BeEquivalentTo("cslopez"))
+
+			Expect(resources[0].GetNextAvailableDate()).Should(Equal(parseDate("2021-06-15")))
+			Expect(resources[1].GetNextAvailableDate()).Should(Equal(parseDate("2021-06-12")))
+			Expect(resources[2].GetNextAvailableDate()).Should(Equal(parseDate("2021-06-15")))
+		})
+
+		It("La agenda de cada recurso debe incluir las tareas compartidas", func() {
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			var timeline []gplan.TaskID
+			for _, booking := range schedule.GetResourceTimeline("ahg") {
+				timeline = append(timeline, booking.TaskID)
+			}
+			Expect(timeline).Should(Equal([]gplan.TaskID{"Tarea1", "Programacion en pareja", "Despliegue"}))
+		})
+	})
+
+	When("una tarea necesita más recursos de los que hay", func() {
+
+		It("Debe devolver un error", func() {
+			tasks[1].ResourceDemands[0].Quantity = 3
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("no hay suficientes recursos para las siguientes tareas")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Programacion en pareja"}))
+		})
+	})
+
+	When("una demanda de recursos no tiene cantidad", func() {
+
+		It("Debe devolver un error", func() {
+			tasks[2].ResourceDemands[1].Quantity = 0
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("las siguientes tareas tienen demandas de recursos sin cantidad")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Despliegue"}))
+		})
+	})
+
+	When("los recursos de una tarea no trabajan ningún día a la vez", func() {
+
+		It("Debe devolver un error", func() {
+			resources[2].Calendar = NewCalendar([]time.Weekday{time.Saturday, time.Sunday}, nil)
+
+			plan := NewProjectPlan("test-plan", tasks, resources, nil)
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Message).Should(Equal(fmt.Errorf("los recursos elegidos para las siguientes tareas no tienen ningún día laborable en común")))
+			Expect(err.Tasks).Should(Equal([]gplan.TaskID{"Despliegue"}))
+		})
+	})
+
+})
@@ -0,0 +1,506 @@
+package gplan
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+const (
+	// EmptyTaskListIssue el plan no tiene tareas
+	EmptyTaskListIssue IssueCode = "empty-task-list"
+	// EmptyResourceListIssue el plan no tiene recursos
+	EmptyResourceListIssue IssueCode = "empty-resource-list"
+	// DuplicateTaskIDIssue hay varias tareas con el mismo ID
+	DuplicateTaskIDIssue IssueCode = "duplicate-task-id"
+	// DuplicateResourceIDIssue hay varios recursos con el mismo ID
+	DuplicateResourceIDIssue IssueCode = "duplicate-resource-id"
+	// InvalidOrderIssue la tarea tiene un número de orden inferior a 1
+	InvalidOrderIssue IssueCode = "invalid-order"
+	// InvalidConstraintIssue la tarea tiene una restricción de fecha desconocida o sin fecha
+	InvalidConstraintIssue IssueCode = "invalid-constraint"
+	// InvalidResourceDemandIssue la tarea tiene una demanda de recursos sin cantidad
+	InvalidResourceDemandIssue IssueCode = "invalid-resource-demand"
+	// DemandsWithTeamIssue la tarea tiene demandas de recursos y reparte su duración entre varios recursos
+	DemandsWithTeamIssue IssueCode = "demands-with-team"
+	// SplittableWithTeamIssue la tarea es divisible y necesita varios recursos
+	SplittableWithTeamIssue IssueCode = "splittable-with-team"
+	// UnknownRequiredResourceIssue la tarea necesita un recurso que no existe
+	UnknownRequiredResourceIssue IssueCode = "unknown-required-resource"
+	// RequiredResourceTypeIssue la tarea necesita un recurso que no puede realizarla o que está excluido
+	RequiredResourceTypeIssue IssueCode = "required-resource-type"
+	// InvalidAllocationIssue el recurso tiene un porcentaje de dedicación que no está entre 1 y 100
+	InvalidAllocationIssue IssueCode = "invalid-allocation"
+	// InvalidAvailabilityIssue el recurso deja de estar disponible antes de estar disponible
+	InvalidAvailabilityIssue IssueCode = "invalid-availability"
+	// UnusedResourceIssue no hay tareas que pueda realizar el recurso
+	UnusedResourceIssue IssueCode = "unused-resource"
+	// MissingResourceTypeIssue no hay recursos del tipo que necesita la tarea
+	MissingResourceTypeIssue IssueCode = "missing-resource-type"
+	// NotEnoughResourcesIssue no hay suficientes recursos del tipo que necesita la tarea
+	NotEnoughResourcesIssue IssueCode = "not-enough-resources"
+	// UnknownDependencyTaskIssue la tarea bloquea o está bloqueada por una tarea que no existe
+	UnknownDependencyTaskIssue IssueCode = "unknown-dependency-task"
+	// UnknownDependencyTypeIssue la tarea tiene una dependencia de un tipo desconocido
+	UnknownDependencyTypeIssue IssueCode = "unknown-dependency-type"
+	// DependencyMismatchIssue la dependencia no tiene el mismo tipo o desfase en las dos tareas
+	DependencyMismatchIssue IssueCode = "dependency-mismatch"
+	// UnmirroredDependencyIssue la dependencia solo aparece en una de las dos tareas
+	UnmirroredDependencyIssue IssueCode = "unmirrored-dependency"
+	// CircularDependencyIssue la tarea se bloquea a sí misma a través de su cadena de dependencias
+	CircularDependencyIssue IssueCode = "circular-dependency"
+	// UnknownPriorityRuleIssue la regla de prioridad del plan es desconocida
+	UnknownPriorityRuleIssue IssueCode = "unknown-priority-rule"
+)
+
+// issueKinds tipo de error de cada problema de validación
+var issueKinds = map[IssueCode]error{
+	EmptyTaskListIssue:           ErrEmptyTaskList,
+	EmptyResourceListIssue:       ErrEmptyResourceList,
+	DuplicateTaskIDIssue:         ErrInvalidTask,
+	DuplicateResourceIDIssue:     ErrInvalidResource,
+	InvalidOrderIssue:            ErrInvalidTask,
+	InvalidConstraintIssue:       ErrInvalidTask,
+	InvalidResourceDemandIssue:   ErrInvalidTask,
+	DemandsWithTeamIssue:         ErrInvalidTask,
+	SplittableWithTeamIssue:      ErrInvalidTask,
+	UnknownRequiredResourceIssue: ErrUnknownResource,
+	RequiredResourceTypeIssue:    ErrResourceCannotDoTask,
+	InvalidAllocationIssue:       ErrInvalidResource,
+	InvalidAvailabilityIssue:     ErrInvalidResource,
+	UnusedResourceIssue:          ErrUnusedResource,
+	MissingResourceTypeIssue:     ErrMissingResourceType,
+	NotEnoughResourcesIssue:      ErrNotEnoughResources,
+	UnknownDependencyTaskIssue:   ErrUnknownDependency,
+	UnknownDependencyTypeIssue:   ErrInvalidDependency,
+	DependencyMismatchIssue:      ErrInvalidDependency,
+	UnmirroredDependencyIssue:    ErrInvalidDependency,
+	CircularDependencyIssue:      ErrCircularDependency,
+	UnknownPriorityRuleIssue:     ErrInvalidPlan,
+}
+
+// Validate comprueba que un plan se puede planificar y devuelve todos los problemas que encuentra de una vez, a
+// diferencia de la planificación, que se detiene en el primer error. Cada problema es un error, que impide planificar,
+// o un aviso, que no lo impide, con un código que identifica su tipo y los IDs de las tareas y los recursos afectados
+func Validate(plan ProjectPlan) []Issue {
+
+	issues := validatePlan(plan.GetTasks(), plan.GetResources())
+
+	if plan.GetPriorityRule() > EarliestConstraintRule {
+		issues = append(issues, Issue{
+			Code:    UnknownPriorityRuleIssue,
+			Message: "la regla de prioridad del plan es desconocida",
+		})
+	}
+
+	return issues
+}
+
+// validateTasks comprueba que las tareas tengan el formato correcto para poder realizar la planificación. Devuelve el
+// primero de los errores y los avisos que no impiden planificar
+func validateTasks(tasks []Task, resources []Resource) (map[TaskID]Task, []*Error, *Error) {
+
+	var warnings []*Error
+
+	for _, issue := range validatePlan(tasks, resources) {
+		err := &Error{
+			Message:   errors.New(issue.Message),
+			Tasks:     issue.Tasks,
+			Resources: issue.Resources,
+			Kind:      issueKinds[issue.Code],
+		}
+		if issue.Severity == ErrorSeverity {
+			return nil, nil, err
+		}
+		warnings = append(warnings, err)
+	}
+
+	// Crea el índice de tareas para poder saber a qué tarea corresponde un tareaID
+	var tasksIndex = make(map[TaskID]Task, len(tasks))
+	for _, task := range tasks {
+		tasksIndex[task.GetID()] = task
+	}
+
+	return tasksIndex, warnings, nil
+}
+
+// validatePlan comprueba las tareas y los recursos de un plan y devuelve todos los problemas que encuentra. Los errores
+// se devuelven en el orden en el que se comprueban, de manera que el primero es el que se ha devuelto siempre al
+// planificar
+func validatePlan(tasks []Task, resources []Resource) []Issue {
+
+	var issues []Issue
+
+	// addIssue añade un problema si hay tareas o recursos afectados
+	addIssue := func(severity IssueSeverity, code IssueCode, message string, taskIDs []TaskID) {
+		if len(taskIDs) > 0 {
+			issues = append(issues, Issue{Severity: severity, Code: code, Message: message, Tasks: taskIDs})
+		}
+	}
+
+	// La lista de tareas no puede estar vacía
+	if len(tasks) == 0 {
+		issues = append(issues, Issue{Code: EmptyTaskListIssue, Message: "la lista de tareas a planificar está vacía"})
+	}
+
+	// La lista de recursos no puede estar vacía
+	if len(resources) == 0 {
+		issues = append(issues, Issue{Code: EmptyResourceListIssue, Message: "la lista de recursos a asignar está vacía"})
+	}
+
+	// No puede haber varias tareas ni varios recursos con el mismo ID
+	var (
+		tasksIndex     = make(map[TaskID]Task, len(tasks))
+		resourcesIndex = make(map[ResourceID]Resource, len(resources))
+		duplicated     []TaskID
+	)
+	for _, task := range tasks {
+		if _, exist := tasksIndex[task.GetID()]; exist && !containsTaskID(duplicated, task.GetID()) {
+			duplicated = append(duplicated, task.GetID())
+		}
+		tasksIndex[task.GetID()] = task
+	}
+	addIssue(ErrorSeverity, DuplicateTaskIDIssue, "las siguientes tareas tienen el ID repetido", duplicated)
+
+	var duplicatedResources []ResourceID
+	for _, resource := range resources {
+		if _, exist := resourcesIndex[resource.GetID()]; exist && !containsResourceID(duplicatedResources, resource.GetID()) {
+			duplicatedResources = append(duplicatedResources, resource.GetID())
+		}
+		resourcesIndex[resource.GetID()] = resource
+	}
+	if len(duplicatedResources) > 0 {
+		issues = append(issues, Issue{
+			Code:      DuplicateResourceIDIssue,
+			Message:   "los siguientes recursos tienen el ID repetido",
+			Resources: duplicatedResources,
+		})
+	}
+
+	// No puede haber tareas con una orden menor que 1
+	var taskIDs []TaskID
+	for _, task := range tasks {
+		if task.GetOrder() < 1 {
+			taskIDs = append(taskIDs, task.GetID())
+		}
+	}
+	addIssue(ErrorSeverity, InvalidOrderIssue, "las siguientes tareas tienen un orden inferior a 1", taskIDs)
+
+	// Las restricciones de fecha tienen que ser de un tipo conocido y tener fecha
+	taskIDs = nil
+	for _, task := range tasks {
+		if task.GetConstraintType() > MustStartOn ||
+			(task.GetConstraintType() != NoConstraint && task.GetConstraintDate().IsZero()) {
+			taskIDs = append(taskIDs, task.GetID())
+		}
+	}
+	addIssue(ErrorSeverity, InvalidConstraintIssue, "las siguientes tareas tienen una restricción de fecha desconocida o sin fecha", taskIDs)
+
+	// Las demandas de recursos tienen que ser de al menos un recurso
+	taskIDs = nil
+	for _, task := range tasks {
+		for _, demand := range task.GetResourceDemands() {
+			if demand.GetQuantity() < 1 {
+				taskIDs = append(taskIDs, task.GetID())
+				break
+			}
+		}
+	}
+	addIssue(ErrorSeverity, InvalidResourceDemandIssue, "las siguientes tareas tienen demandas de recursos sin cantidad", taskIDs)
+
+	// Una tarea no puede necesitar varios recursos a la vez y repartir su duración entre varios recursos
+	taskIDs = nil
+	for _, task := range tasks {
+		if task.GetMaxTeamSize() > 1 && len(task.GetResourceDemands()) > 0 {
+			taskIDs = append(taskIDs, task.GetID())
+		}
+	}
+	addIssue(ErrorSeverity, DemandsWithTeamIssue, "las siguientes tareas tienen demandas de recursos y reparten su duración entre varios recursos", taskIDs)
+
+	// Una tarea divisible se realiza por tramos de un solo recurso, no puede necesitar varios recursos a la vez ni
+	// repartir su duración entre varios recursos
+	taskIDs = nil
+	for _, task := range tasks {
+		if task.IsSplittable() && (task.GetMaxTeamSize() > 1 || len(task.GetResourceDemands()) > 0) {
+			taskIDs = append(taskIDs, task.GetID())
+		}
+	}
+	addIssue(ErrorSeverity, SplittableWithTeamIssue, "las siguientes tareas son divisibles y necesitan varios recursos", taskIDs)
+
+	// El recurso que necesita una tarea tiene que existir, poder realizarla y no estar excluido
+	var (
+		unknownRequired []TaskID
+		wrongRequired   []TaskID
+	)
+	for _, task := range tasks {
+		required := task.GetRequiredResourceID()
+		if required == nil {
+			continue
+		}
+		resource, exist := resourcesIndex[*required]
+		if !exist {
+			unknownRequired = append(unknownRequired, task.GetID())
+			continue
+		}
+		if IsMilestone(task) {
+			continue
+		}
+		efficiencies := resourceEfficiencies(resource)
+		canDo := false
+		for _, demand := range taskDemands(task) {
+			if _, exist := efficiencies[demand.GetResourceType()]; exist {
+				canDo = true
+			}
+		}
+		if !canDo || containsResourceID(task.GetExcludedResourceIDs(), *required) {
+			wrongRequired = append(wrongRequired, task.GetID())
+		}
+	}
+	addIssue(ErrorSeverity, UnknownRequiredResourceIssue, "las siguientes tareas necesitan un recurso que no existe", unknownRequired)
+	addIssue(ErrorSeverity, RequiredResourceTypeIssue, "las siguientes tareas necesitan un recurso que no puede realizarlas", wrongRequired)
+
+	// Los porcentajes de dedicación de los recursos tienen que estar entre 1 y 100
+	for _, resource := range resources {
+		for _, allocation := range resource.GetAllocations() {
+			if allocation.GetPercentage() < 1 || allocation.GetPercentage() > 100 {
+				issues = append(issues, Issue{
+					Code:      InvalidAllocationIssue,
+					Message:   fmt.Sprintf("el recurso %s tiene un porcentaje de dedicación que no está entre 1 y 100", resource.GetID()),
+					Resources: []ResourceID{resource.GetID()},
+				})
+				break
+			}
+		}
+	}
+
+	// Un recurso no puede dejar de estar disponible antes de estarlo
+	for _, resource := range resources {
+		if !resource.GetAvailableUntil().IsZero() && dateutil.IsLt(resource.GetAvailableUntil(), resource.GetAvailableFrom()) {
+			issues = append(issues, Issue{
+				Code:      InvalidAvailabilityIssue,
+				Message:   fmt.Sprintf("el recurso %s deja de estar disponible antes de estar disponible", resource.GetID()),
+				Resources: []ResourceID{resource.GetID()},
+			})
+		}
+	}
+
+	// Ha de haber recursos que puedan realizar cada tipo de tarea. Si un recurso no puede realizar ninguna de las
+	// tareas se avisa
+	var (
+		typeOfTasks     = make(map[string]bool)
+		typeOfResources = make(map[string]uint)
+	)
+
+	// Crea el índice de tipos de tarea, los hitos no necesitan recurso
+	for _, task := range tasks {
+		if !IsMilestone(task) {
+			for _, demand := range taskDemands(task) {
+				typeOfTasks[demand.GetResourceType()] = true
+			}
+		}
+	}
+
+	// Crea el índice de tipos de resource y avisa de los recursos que no pueden realizar ninguna tarea
+	for _, resource := range resources {
+		var types []string
+		for resourceType := range resourceEfficiencies(resource) {
+			typeOfResources[resourceType]++
+			types = append(types, resourceType)
+		}
+		if !hasAnyType(typeOfTasks, types) {
+			sort.Strings(types)
+			issues = append(issues, Issue{
+				Severity: WarningSeverity,
+				Code:     UnusedResourceIssue,
+				Message: fmt.Sprintf("no existen tareas para el recurso %s de tipo %s", resource.GetID(),
+					strings.Join(types, ", ")),
+				Resources: []ResourceID{resource.GetID()},
+			})
+		}
+	}
+
+	// Tiene que haber recursos para las tareas del tipo que llevan asociado
+	taskIDs = nil
+	for _, task := range tasks {
+		for _, demand := range taskDemands(task) {
+			if _, exist := typeOfResources[demand.GetResourceType()]; !exist && !IsMilestone(task) {
+				taskIDs = append(taskIDs, task.GetID())
+				break
+			}
+		}
+	}
+	addIssue(ErrorSeverity, MissingResourceTypeIssue, "no hay recursos para los tipos de estas tareas", taskIDs)
+
+	// Tiene que haber suficientes recursos de cada tipo para las tareas que necesitan varios a la vez. Si no hay
+	// ningún recurso del tipo ya se ha avisado
+	taskIDs = nil
+	for _, task := range tasks {
+		var quantities = make(map[string]uint)
+		for _, demand := range taskDemands(task) {
+			quantities[demand.GetResourceType()] += demand.GetQuantity()
+		}
+		for resourceType, quantity := range quantities {
+			if quantity > typeOfResources[resourceType] && typeOfResources[resourceType] > 0 && !IsMilestone(task) {
+				taskIDs = append(taskIDs, task.GetID())
+				break
+			}
+		}
+	}
+	addIssue(ErrorSeverity, NotEnoughResourcesIssue, "no hay suficientes recursos para las siguientes tareas", taskIDs)
+
+	// No puede haber tareas en blocksBy ni blocksTo que no estén en la lista de tareas
+	taskIDs = nil
+	for _, task := range tasks {
+		for _, dep := range append(task.GetBlocksTo(), task.GetBlocksBy()...) {
+			if _, exist := tasksIndex[dep.GetTaskID()]; !exist && !containsTaskID(taskIDs, dep.GetTaskID()) {
+				taskIDs = append(taskIDs, dep.GetTaskID())
+			}
+		}
+	}
+	addIssue(ErrorSeverity, UnknownDependencyTaskIssue, "hay tareas bloquedas o que bloquean a otras que no existen en la lista de tareas", taskIDs)
+
+	// Las dependencias tienen que ser de un tipo conocido
+	taskIDs = nil
+	for _, task := range tasks {
+		if hasUnknownDependencyType(task.GetBlocksTo()) || hasUnknownDependencyType(task.GetBlocksBy()) {
+			taskIDs = append(taskIDs, task.GetID())
+		}
+	}
+	addIssue(ErrorSeverity, UnknownDependencyTypeIssue, "hay tareas con dependencias de un tipo desconocido", taskIDs)
+
+	// Si una dependencia aparece en blocksTo de una tarea y en blocksBy de la otra, ambas deben tener el mismo tipo y
+	// desfase. Si solo aparece en una de ellas se avisa, la planificación la tiene en cuenta igualmente en las dos tareas
+	taskIDs = nil
+	for _, task := range tasks {
+		for _, dep := range task.GetBlocksTo() {
+			blocked, exist := tasksIndex[dep.GetTaskID()]
+			if !exist {
+				continue
+			}
+			reverseDep := findDependency(blocked.GetBlocksBy(), task.GetID())
+			if reverseDep == nil {
+				issues = append(issues, Issue{
+					Severity: WarningSeverity,
+					Code:     UnmirroredDependencyIssue,
+					Message: fmt.Sprintf("la tarea %s bloquea a la tarea %s pero no aparece entre las tareas que la bloquean",
+						task.GetID(), dep.GetTaskID()),
+					Tasks: []TaskID{task.GetID(), dep.GetTaskID()},
+				})
+				continue
+			}
+			if reverseDep.GetType() != dep.GetType() || reverseDep.GetLag() != dep.GetLag() {
+				taskIDs = append(taskIDs, dep.GetTaskID())
+			}
+		}
+		for _, dep := range task.GetBlocksBy() {
+			blocking, exist := tasksIndex[dep.GetTaskID()]
+			if exist && findDependency(blocking.GetBlocksTo(), task.GetID()) == nil {
+				issues = append(issues, Issue{
+					Severity: WarningSeverity,
+					Code:     UnmirroredDependencyIssue,
+					Message: fmt.Sprintf("la tarea %s está bloqueada por la tarea %s pero no aparece entre las tareas a las que bloquea",
+						task.GetID(), dep.GetTaskID()),
+					Tasks: []TaskID{dep.GetTaskID(), task.GetID()},
+				})
+			}
+		}
+	}
+	addIssue(ErrorSeverity, DependencyMismatchIssue, "hay tareas cuyas dependencias no coinciden en tipo o desfase con las de la tarea que las bloquea", taskIDs)
+
+	// No puede haber referencias circulares, es decir, tareas que se bloqueen a sí mismas
+	taskIDs = nil
+	var (
+		successors = taskSuccessors(tasks)
+		inPath     = make(map[TaskID]bool)
+		clean      = make(map[TaskID]bool, len(tasks))
+	)
+	for _, task := range tasks {
+		if taskID := checkForCircularDependencies(task.GetID(), successors, tasksIndex, inPath, clean); taskID != nil && !containsTaskID(taskIDs, *taskID) {
+			taskIDs = append(taskIDs, *taskID)
+			issues = append(issues, Issue{
+				Code:    CircularDependencyIssue,
+				Message: fmt.Sprintf("%s contiene una referencia circular en su cadena de dependencias", *taskID),
+				Tasks:   []TaskID{*taskID},
+			})
+		}
+	}
+
+	return issues
+}
+
+// hasAnyType devuelve True si alguno de los tipos está en el índice de tipos
+func hasAnyType(index map[string]bool, types []string) bool {
+	for _, t := range types {
+		if index[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTaskID devuelve True si la tarea está en la lista
+func containsTaskID(taskIDs []TaskID, taskID TaskID) bool {
+	for _, id := range taskIDs {
+		if id == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// findDependency devuelve la dependencia con una tarea o nil si no la hay
+func findDependency(deps []TaskDependency, taskID TaskID) TaskDependency {
+	for _, dep := range deps {
+		if dep.GetTaskID() == taskID {
+			return dep
+		}
+	}
+	return nil
+}
+
+// hasUnknownDependencyType devuelve true si alguna de las dependencias no es de uno de los tipos conocidos
+func hasUnknownDependencyType(deps []TaskDependency) bool {
+	for _, dep := range deps {
+		if dep.GetType() > StartToFinish {
+			return true
+		}
+	}
+	return false
+}
+
+// checkForCircularDependencies Chequea que no haya referencias circulares en los bloqueos de las tareas, es decir que
+// una tarea se bloquee a sí misma. Recorre en profundidad las tareas a las que bloquea cada tarea, tanto por su blocksTo
+// como por el blocksBy de las otras tareas. Devuelve la tarea que cierra el círculo o nil si no lo hay. Las tareas que
+// no están en el índice no se recorren.
+// inPath contiene las tareas del recorrido actual y clean las que ya se sabe que no llevan a ningún círculo, que no se
+// vuelven a recorrer para que el coste no crezca con el número de caminos.
+// Por ejemplo: A → B → C → A ...
+func checkForCircularDependencies(taskID TaskID, successors map[TaskID][]TaskID, tasksIndex map[TaskID]Task,
+	inPath map[TaskID]bool, clean map[TaskID]bool) *TaskID {
+
+	if inPath[taskID] {
+		return &taskID
+	}
+	if clean[taskID] {
+		return nil
+	}
+
+	inPath[taskID] = true
+	defer delete(inPath, taskID)
+
+	// Recorre las tareas a las que está bloqueando llamando recursivamente a la propia función
+	for _, succ := range successors[taskID] {
+		if _, exist := tasksIndex[succ]; !exist {
+			continue
+		}
+		if circular := checkForCircularDependencies(succ, successors, tasksIndex, inPath, clean); circular != nil {
+			return circular
+		}
+	}
+
+	clean[taskID] = true
+	return nil
+}
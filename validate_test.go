@@ -0,0 +1,142 @@
+package gplan_test
+
+import (
+	"fmt"
+
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 3),
+			NewTask("Tarea2", "Summary", "backend", 20, 3),
+			NewTask("QA", "Summary", "qa", 30, 2),
+		}
+
+		BlocksTo(tasks[0], tasks[1])
+		BlocksTo(tasks[1], tasks[2])
+
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+	})
+
+	When("el plan es correcto", func() {
+
+		It("No debe encontrar ningún problema", func() {
+			Expect(gplan.Validate(plan)).Should(BeEmpty())
+		})
+	})
+
+	When("el plan tiene varios problemas", func() {
+
+		It("Debe devolver todos los problemas a la vez", func() {
+			plan.Tasks[0].Order = 0
+			plan.Tasks = append(plan.Tasks, NewTask("Tarea1", "Summary", "backend", 40, 1))
+			BlocksTo(plan.Tasks[2], plan.Tasks[1])
+			plan.Resources = append(plan.Resources,
+				NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), nil),
+				NewResource("devops", "Devops", "devops", parseDate("2021-06-07"), nil))
+
+			issues := gplan.Validate(plan)
+			Expect(compareIssues(issues)).Should(Equal([]gplan.IssueCode{
+				gplan.DuplicateTaskIDIssue,
+				gplan.DuplicateResourceIDIssue,
+				gplan.InvalidOrderIssue,
+				gplan.UnusedResourceIssue,
+				gplan.UnmirroredDependencyIssue,
+				gplan.CircularDependencyIssue,
+				gplan.CircularDependencyIssue,
+			}))
+
+			Expect(issues[0].Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+			Expect(issues[1].Resources).Should(Equal([]gplan.ResourceID{"ahg"}))
+			Expect(issues[2].Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+			Expect(issues[3].Severity).Should(Equal(gplan.WarningSeverity))
+			Expect(issues[3].Resources).Should(Equal([]gplan.ResourceID{"devops"}))
+			Expect(issues[4].Severity).Should(Equal(gplan.WarningSeverity))
+			Expect(issues[4].Tasks).Should(Equal([]gplan.TaskID{"Tarea1", "Tarea2"}))
+			Expect(issues[5].Severity).Should(Equal(gplan.ErrorSeverity))
+			Expect(issues[5].Tasks).Should(Equal([]gplan.TaskID{"Tarea2"}))
+			Expect(issues[6].Tasks).Should(Equal([]gplan.TaskID{"QA"}))
+		})
+	})
+
+	When("una dependencia solo aparece en una de las tareas", func() {
+
+		It("Debe avisar y planificar igualmente", func() {
+			plan.Tasks[2].BlocksBy = nil
+
+			issues := gplan.Validate(plan)
+			Expect(compareIssues(issues)).Should(Equal([]gplan.IssueCode{gplan.UnmirroredDependencyIssue}))
+			Expect(issues[0].Tasks).Should(Equal([]gplan.TaskID{"Tarea2", "QA"}))
+
+			schedule, err := gplan.PlanSchedule(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+			Expect(schedule.GetWarnings()).Should(HaveLen(1))
+		})
+
+		It("La tarea bloqueada debe comenzar después de la que la bloquea aunque solo aparezca en blocksTo", func() {
+			// Tarea1 bloquea a QA pero QA no la tiene entre las tareas que la bloquean
+			plan.Tasks[1].BlocksTo = nil
+			plan.Tasks[2].BlocksBy = nil
+			plan.Tasks[0].BlocksTo = append(plan.Tasks[0].BlocksTo, NewTaskDependency("QA"))
+
+			err := gplan.Planning(parseDate("2021-06-07"), plan)
+			Expect(err).Should(BeNil())
+
+			comparePlan(plan.Tasks, []string{
+				"2021-06-07 2021-06-09 ahg",
+				"2021-06-10 2021-06-14 ahg",
+				"2021-06-10 2021-06-11 Noemi",
+			})
+			Expect(gplan.Verify(plan)).Should(BeEmpty())
+		})
+	})
+
+	When("el plan tiene muchas tareas encadenadas por varios caminos", func() {
+
+		It("Debe validarlo sin recorrer cada camino", func() {
+			// 26 niveles de dos tareas en los que cada tarea bloquea a las dos del nivel siguiente: 2^26 caminos
+			var tasks []*Task
+			for i := 0; i < 52; i++ {
+				task := NewTask(gplan.TaskID(fmt.Sprintf("Tarea%d", i+1)), "Summary", "backend", uint(i+1), 1)
+				if i >= 2 {
+					level := i / 2
+					BlocksTo(tasks[2*(level-1)], task)
+					BlocksTo(tasks[2*(level-1)+1], task)
+				}
+				tasks = append(tasks, task)
+			}
+			plan.Tasks = tasks
+			plan.Resources = plan.Resources[:1]
+
+			done := make(chan []gplan.Issue)
+			go func() {
+				done <- gplan.Validate(plan)
+			}()
+			Eventually(done, "5s").Should(Receive(BeEmpty()))
+		})
+	})
+
+	When("una tarea depende de una tarea que no existe", func() {
+
+		It("Debe devolver la tarea que no existe", func() {
+			BlocksTo(plan.Tasks[2], NewTask("Tarea4", "Summary", "qa", 40, 1))
+
+			issues := gplan.Validate(plan)
+			Expect(compareIssues(issues)).Should(Equal([]gplan.IssueCode{gplan.UnknownDependencyTaskIssue}))
+			Expect(issues[0].Tasks).Should(Equal([]gplan.TaskID{"Tarea4"}))
+		})
+	})
+
+})
@@ -0,0 +1,422 @@
+package gplan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/antoniohueso/gplan/dateutil"
+)
+
+// IssueSeverity gravedad de un problema encontrado en un plan
+type IssueSeverity uint
+
+const (
+	// ErrorSeverity el problema hace que el plan no sea válido
+	ErrorSeverity IssueSeverity = iota
+	// WarningSeverity el problema no impide que el plan sea válido pero conviene revisarlo
+	WarningSeverity
+)
+
+// IssueCode código que identifica el tipo de un problema encontrado en un plan
+type IssueCode string
+
+const (
+	// UnplannedTaskIssue la tarea no tiene fechas o no tiene recurso
+	UnplannedTaskIssue IssueCode = "unplanned-task"
+	// UnknownResourceIssue la tarea tiene asignado un recurso que no existe
+	UnknownResourceIssue IssueCode = "unknown-resource"
+	// ResourceTypeIssue la tarea tiene asignado un recurso que no puede realizarla
+	ResourceTypeIssue IssueCode = "resource-type"
+	// NonLaborableDateIssue la tarea comienza o termina en un día en el que no trabaja alguno de sus recursos
+	NonLaborableDateIssue IssueCode = "non-laborable-date"
+	// DurationIssue los días de trabajo entre el comienzo y el fin de la tarea no coinciden con su duración
+	DurationIssue IssueCode = "duration"
+	// OverallocationIssue un recurso tiene dos tareas el mismo día laborable
+	OverallocationIssue IssueCode = "overallocation"
+	// DependencyIssue la tarea no cumple alguna de sus dependencias
+	DependencyIssue IssueCode = "dependency"
+	// ConstraintIssue la tarea no cumple su restricción de fecha
+	ConstraintIssue IssueCode = "constraint"
+	// PlanDatesIssue las fechas de comienzo o fin del plan no coinciden con las de sus tareas
+	PlanDatesIssue IssueCode = "plan-dates"
+	// PlanTotalsIssue los totales del plan no coinciden con sus tareas
+	PlanTotalsIssue IssueCode = "plan-totals"
+)
+
+// Issue problema encontrado en un plan
+type Issue struct {
+	// Gravedad del problema
+	Severity IssueSeverity
+	// Tipo del problema
+	Code IssueCode
+	// Descripción del problema
+	Message string
+	// Tareas afectadas
+	Tasks []TaskID
+	// Recursos afectados
+	Resources []ResourceID
+}
+
+// Verify comprueba que un plan ya planificado es coherente, por ejemplo después de modificar sus fechas a mano, y
+// devuelve todos los problemas que encuentra: tareas sin planificar, recursos que no existen o que no pueden realizar
+// sus tareas, tareas que comienzan o terminan en días no laborables para sus recursos, duraciones que no coinciden
+// con los días de trabajo, recursos con dos tareas el mismo día laborable, dependencias y restricciones de fecha que
+// no se cumplen y fechas y totales del plan que no coinciden con sus tareas. No modifica el plan
+func Verify(plan ProjectPlan) []Issue {
+
+	var (
+		tasks          = plan.GetTasks()
+		resourcesIndex = make(map[ResourceID]Resource)
+		planned        []Task
+		issues         []Issue
+	)
+
+	for _, resource := range plan.GetResources() {
+		resourcesIndex[resource.GetID()] = resource
+	}
+
+	// El planner contiene los calendarios y perfiles con los que se ha planificado
+	p := newPlanner(plan.GetStartDate().Local(), plan, make(map[TaskID]Task, len(tasks)))
+
+	for _, task := range tasks {
+		if !isPlanned(task) || task.GetEndDate().IsZero() {
+			issues = append(issues, Issue{
+				Code:    UnplannedTaskIssue,
+				Message: fmt.Sprintf("la tarea %s no está planificada", task.GetID()),
+				Tasks:   []TaskID{task.GetID()},
+			})
+			continue
+		}
+
+		// Los recursos de sus tramos también tienen que existir, si no la tarea no se puede comprobar
+		var (
+			resourceIDs = taskResourceIDs(task)
+			unknown     []ResourceID
+		)
+		for _, segment := range task.GetSegments() {
+			if !containsResourceID(resourceIDs, segment.ResourceID) {
+				resourceIDs = append(resourceIDs, segment.ResourceID)
+			}
+		}
+		for _, resourceID := range resourceIDs {
+			if _, exist := resourcesIndex[resourceID]; !exist {
+				unknown = append(unknown, resourceID)
+			}
+		}
+		if len(unknown) > 0 {
+			issues = append(issues, Issue{
+				Code:      UnknownResourceIssue,
+				Message:   fmt.Sprintf("la tarea %s tiene asignado un recurso que no existe", task.GetID()),
+				Tasks:     []TaskID{task.GetID()},
+				Resources: unknown,
+			})
+			continue
+		}
+
+		p.tasksIndex[task.GetID()] = task
+		p.assignments[task.GetID()] = &Assignment{
+			TaskID:    task.GetID(),
+			StartDate: task.GetStartDate().Local(),
+			EndDate:   task.GetEndDate().Local(),
+			Milestone: IsMilestone(task),
+		}
+		planned = append(planned, task)
+	}
+
+	for _, task := range planned {
+		issues = append(issues, p.verifyTask(task)...)
+	}
+
+	issues = append(issues, p.verifyOverallocation(planned)...)
+
+	for _, task := range planned {
+		issues = append(issues, p.verifyDependencies(task)...)
+	}
+
+	issues = append(issues, p.verifyPlan(plan, planned)...)
+
+	return issues
+}
+
+// verifyTask comprueba los recursos, las fechas, la duración y la restricción de fecha de una tarea
+func (p *planner) verifyTask(task Task) []Issue {
+
+	var (
+		issues    []Issue
+		startDate = task.GetStartDate().Local()
+		endDate   = task.GetEndDate().Local()
+	)
+
+	if IsMilestone(task) {
+		if !p.calendar.isLaborableDay(startDate) {
+			issues = append(issues, Issue{
+				Code:    NonLaborableDateIssue,
+				Message: fmt.Sprintf("el hito %s está en un día no laborable", task.GetID()),
+				Tasks:   []TaskID{task.GetID()},
+			})
+		}
+		return append(issues, p.verifyConstraint(task, startDate, endDate)...)
+	}
+
+	var (
+		profiles   []*resourceProfile
+		taskTypes  []string
+		wrongType  []ResourceID
+		notWorking []ResourceID
+	)
+
+	for _, resourceID := range taskResourceIDs(task) {
+		profile := p.resourceProfiles[resourceID]
+		taskType := profile.demandedType(task)
+		if !profile.canDo(taskType) {
+			wrongType = append(wrongType, resourceID)
+		}
+		profiles = append(profiles, profile)
+		taskTypes = append(taskTypes, taskType)
+	}
+
+	if len(wrongType) > 0 {
+		issues = append(issues, Issue{
+			Code:      ResourceTypeIssue,
+			Message:   fmt.Sprintf("la tarea %s tiene asignados recursos que no pueden realizarla", task.GetID()),
+			Tasks:     []TaskID{task.GetID()},
+			Resources: wrongType,
+		})
+	}
+
+	// Cada recurso tiene que trabajar el día en el que empieza y el día en el que termina su parte de la tarea
+	for _, segment := range taskSegments(task) {
+		profile := p.resourceProfiles[segment.ResourceID]
+		if profile.capacity(segment.StartDate.Local()) == 0 || profile.capacity(segment.EndDate.Local()) == 0 {
+			notWorking = append(notWorking, segment.ResourceID)
+		}
+	}
+	if len(notWorking) > 0 {
+		issues = append(issues, Issue{
+			Code:      NonLaborableDateIssue,
+			Message:   fmt.Sprintf("la tarea %s comienza o termina en un día no laborable para sus recursos", task.GetID()),
+			Tasks:     []TaskID{task.GetID()},
+			Resources: notWorking,
+		})
+	}
+
+	// El trabajo entre el comienzo y el fin tiene que completar la duración y no completarla antes del último día
+	var (
+		duration   = task.GetDuration() * 100
+		work       uint
+		workBefore uint
+	)
+	switch {
+	case len(task.GetSegments()) > 0:
+		for _, segment := range task.GetSegments() {
+			profile := p.resourceProfiles[segment.ResourceID]
+			work += teamWork([]*resourceProfile{profile}, []string{profile.demandedType(task)},
+				segment.StartDate.Local(), segment.EndDate.Local())
+		}
+	case task.GetMaxTeamSize() > 1:
+		// Cada recurso trabaja en las fechas de su parte de la tarea
+		for i, effort := range taskEfforts(task) {
+			work += teamWork(profiles[i:i+1], taskTypes[i:i+1], effort.StartDate, effort.EndDate)
+		}
+	default:
+		work = teamWork(profiles, taskTypes, startDate, endDate)
+		workBefore = teamWork(profiles, taskTypes, startDate, endDate.AddDate(0, 0, -1))
+	}
+	if work < duration || (workBefore >= duration && task.GetMaxTeamSize() <= 1 && len(task.GetSegments()) == 0) {
+		issues = append(issues, Issue{
+			Code: DurationIssue,
+			Message: fmt.Sprintf("los días de trabajo de la tarea %s entre %s y %s no coinciden con su duración de %d días",
+				task.GetID(), startDate.Format("2006-01-02"), endDate.Format("2006-01-02"), task.GetDuration()),
+			Tasks:     []TaskID{task.GetID()},
+			Resources: taskResourceIDs(task),
+		})
+	}
+
+	return append(issues, p.verifyConstraint(task, startDate, endDate)...)
+}
+
+// verifyConstraint comprueba la restricción de fecha de una tarea
+func (p *planner) verifyConstraint(task Task, startDate time.Time, endDate time.Time) []Issue {
+
+	if p.meetsConstraint(task, startDate, endDate) {
+		return nil
+	}
+
+	return []Issue{{
+		Code:    ConstraintIssue,
+		Message: fmt.Sprintf("la tarea %s no cumple su restricción de fecha", task.GetID()),
+		Tasks:   []TaskID{task.GetID()},
+	}}
+}
+
+// taskSegments devuelve los intervalos de trabajo de cada recurso de una tarea planificada: sus tramos si está dividida
+// o las fechas en las que trabaja cada uno de sus recursos si no
+func taskSegments(task Task) []Segment {
+
+	if segments := task.GetSegments(); len(segments) > 0 {
+		return segments
+	}
+
+	var segments []Segment
+	for _, effort := range taskEfforts(task) {
+		segments = append(segments, Segment{
+			ResourceID: effort.ResourceID,
+			StartDate:  effort.StartDate,
+			EndDate:    effort.EndDate,
+			Days:       effort.Days,
+		})
+	}
+	return segments
+}
+
+// verifyOverallocation comprueba que ningún recurso tenga dos tareas el mismo día laborable
+func (p *planner) verifyOverallocation(tasks []Task) []Issue {
+
+	type interval struct {
+		taskID    TaskID
+		startDate time.Time
+		endDate   time.Time
+	}
+
+	var (
+		byResource = make(map[ResourceID][]interval)
+		resources  []ResourceID
+		issues     []Issue
+	)
+
+	for _, task := range tasks {
+		if IsMilestone(task) {
+			continue
+		}
+		for _, segment := range taskSegments(task) {
+			if _, exist := byResource[segment.ResourceID]; !exist {
+				resources = append(resources, segment.ResourceID)
+			}
+			byResource[segment.ResourceID] = append(byResource[segment.ResourceID],
+				interval{taskID: task.GetID(), startDate: segment.StartDate.Local(), endDate: segment.EndDate.Local()})
+		}
+	}
+
+	for _, resourceID := range resources {
+		intervals := byResource[resourceID]
+		sort.SliceStable(intervals, func(i, j int) bool {
+			return dateutil.IsLt(intervals[i].startDate, intervals[j].startDate)
+		})
+
+		profile := p.resourceProfiles[resourceID]
+		for i := range intervals {
+			for j := i + 1; j < len(intervals) && dateutil.IsLte(intervals[j].startDate, intervals[i].endDate); j++ {
+				if intervals[i].taskID == intervals[j].taskID {
+					continue
+				}
+
+				// Solo hay sobreasignación si coinciden en algún día en el que trabaja el recurso
+				to := intervals[i].endDate
+				if dateutil.IsLt(intervals[j].endDate, to) {
+					to = intervals[j].endDate
+				}
+				for date := intervals[j].startDate; dateutil.IsLte(date, to); date = date.AddDate(0, 0, 1) {
+					if profile.capacity(date) > 0 {
+						issues = append(issues, Issue{
+							Code: OverallocationIssue,
+							Message: fmt.Sprintf("el recurso %s tiene las tareas %s y %s el %s", resourceID,
+								intervals[i].taskID, intervals[j].taskID, date.Format("2006-01-02")),
+							Tasks:     []TaskID{intervals[i].taskID, intervals[j].taskID},
+							Resources: []ResourceID{resourceID},
+						})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// verifyDependencies comprueba que una tarea cumple las dependencias con las tareas que la bloquean
+func (p *planner) verifyDependencies(task Task) []Issue {
+
+	var (
+		issues    []Issue
+		startDate = task.GetStartDate().Local()
+		endDate   = task.GetEndDate().Local()
+	)
+
+	for _, dep := range p.blocksBy[task.GetID()] {
+		assignment := p.assignments[dep.GetTaskID()]
+		if assignment == nil {
+			continue
+		}
+
+		minStartDate, minEndDate := p.dependencyDates(dep, assignment)
+
+		var meets bool
+		if IsMilestone(task) {
+			// Un hito tiene que estar como pronto el día laborable siguiente a la fecha mínima de fin
+			meets = dateutil.IsGte(startDate, minStartDate) &&
+				(minEndDate.IsZero() || dateutil.IsGte(startDate, p.calendar.laborableDate(minEndDate, 1)))
+		} else {
+			meets = dateutil.IsGte(startDate, minStartDate) && dateutil.IsGte(endDate, minEndDate)
+		}
+
+		if !meets {
+			issues = append(issues, Issue{
+				Code:    DependencyIssue,
+				Message: fmt.Sprintf("la tarea %s no cumple su dependencia con la tarea %s", task.GetID(), dep.GetTaskID()),
+				Tasks:   []TaskID{dep.GetTaskID(), task.GetID()},
+			})
+		}
+	}
+
+	return issues
+}
+
+// verifyPlan comprueba que las fechas y los totales del plan coinciden con sus tareas
+func (p *planner) verifyPlan(plan ProjectPlan, tasks []Task) []Issue {
+
+	var (
+		issues        []Issue
+		startDate     time.Time
+		endDate       time.Time
+		totalDuration uint
+	)
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	for i, task := range tasks {
+		taskEndDate := task.GetEndDate().Local()
+		if IsMilestone(task) {
+			taskEndDate = p.calendar.laborableDate(taskEndDate, -1)
+		}
+		if i == 0 || dateutil.IsLt(task.GetStartDate().Local(), startDate) {
+			startDate = task.GetStartDate().Local()
+		}
+		if i == 0 || dateutil.IsGt(taskEndDate, endDate) {
+			endDate = taskEndDate
+		}
+		totalDuration += task.GetDuration()
+	}
+
+	if !dateutil.IsEqual(plan.GetStartDate(), startDate) || !dateutil.IsEqual(plan.GetEndDate(), endDate) {
+		issues = append(issues, Issue{
+			Code: PlanDatesIssue,
+			Message: fmt.Sprintf("las fechas del plan deberían ser %s y %s", startDate.Format("2006-01-02"),
+				endDate.Format("2006-01-02")),
+		})
+	}
+
+	if plan.GetTotalTasks() != uint(len(plan.GetTasks())) || plan.GetTotalDuration() != totalDuration ||
+		plan.GetWorkdays() != p.calendar.laborableDays(startDate, endDate) {
+		issues = append(issues, Issue{
+			Code: PlanTotalsIssue,
+			Message: fmt.Sprintf("los totales del plan deberían ser %d tareas, %d días de duración y %d jornadas",
+				len(plan.GetTasks()), totalDuration, p.calendar.laborableDays(startDate, endDate)),
+		})
+	}
+
+	return issues
+}
@@ -0,0 +1,123 @@
+package gplan_test
+
+import (
+	"github.com/antoniohueso/gplan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func compareIssues(issues []gplan.Issue) []gplan.IssueCode {
+	var result []gplan.IssueCode
+	for _, issue := range issues {
+		result = append(result, issue.Code)
+	}
+	return result
+}
+
+var _ = Describe("Verify", func() {
+
+	var plan *ProjectPlan
+
+	BeforeEach(func() {
+		resources := []*Resource{
+			NewResource("ahg", "Antonio Hueso", "backend", parseDate("2021-06-07"), []*Holidays{
+				NewHolidays(parseDate("2021-06-16"), parseDate("2021-06-16")),
+			}),
+			NewResource("Noemi", "Noe Medina", "qa", parseDate("2021-06-07"), nil),
+		}
+
+		tasks := []*Task{
+			NewTask("Tarea1", "Summary", "backend", 10, 3),
+			NewTask("Tarea2", "Summary", "backend", 20, 3),
+			NewTask("QA", "Summary", "qa", 30, 2),
+		}
+
+		BlocksTo(tasks[1], tasks[2])
+
+		plan = NewProjectPlan("test-plan", tasks, resources, nil)
+
+		err := gplan.Planning(parseDate("2021-06-07"), plan)
+		Expect(err).Should(BeNil())
+	})
+
+	When("el plan no se ha modificado después de planificarlo", func() {
+
+		It("No debe encontrar ningún problema", func() {
+			Expect(gplan.Verify(plan)).Should(BeEmpty())
+		})
+	})
+
+	When("un recurso tiene dos tareas el mismo día", func() {
+
+		It("Debe devolver la sobreasignación del recurso", func() {
+			plan.Tasks[1].StartDate = parseDate("2021-06-09")
+			plan.Tasks[1].EndDate = parseDate("2021-06-11")
+
+			issues := gplan.Verify(plan)
+			Expect(compareIssues(issues)).Should(Equal([]gplan.IssueCode{gplan.OverallocationIssue}))
+			Expect(issues[0].Tasks).Should(Equal([]gplan.TaskID{"Tarea1", "Tarea2"}))
+			Expect(issues[0].Resources).Should(Equal([]gplan.ResourceID{"ahg"}))
+			Expect(issues[0].Severity).Should(Equal(gplan.ErrorSeverity))
+		})
+	})
+
+	When("una tarea empieza en fin de semana y no cumple sus dependencias", func() {
+
+		It("Debe devolver todos los problemas", func() {
+			plan.Tasks[2].StartDate = parseDate("2021-06-13")
+			plan.Tasks[2].EndDate = parseDate("2021-06-14")
+
+			Expect(compareIssues(gplan.Verify(plan))).Should(Equal([]gplan.IssueCode{
+				gplan.NonLaborableDateIssue,
+				gplan.DurationIssue,
+				gplan.DependencyIssue,
+				gplan.PlanDatesIssue,
+				gplan.PlanTotalsIssue,
+			}))
+		})
+	})
+
+	When("una tarea tiene un recurso que no puede realizarla", func() {
+
+		It("Debe devolver el recurso", func() {
+			plan.Tasks[2].ResourceID = &plan.Resources[0].ID
+			plan.Tasks[2].ResourceIDs = []gplan.ResourceID{plan.Resources[0].ID}
+			plan.Tasks[2].StartDate = parseDate("2021-06-17")
+			plan.Tasks[2].EndDate = parseDate("2021-06-18")
+
+			issues := gplan.Verify(plan)
+			Expect(compareIssues(issues)).Should(ContainElement(gplan.ResourceTypeIssue))
+			Expect(issues[0].Resources).Should(Equal([]gplan.ResourceID{"ahg"}))
+		})
+	})
+
+	When("un tramo de una tarea tiene un recurso que no existe", func() {
+
+		It("Debe devolver el recurso", func() {
+			plan.Tasks[0].Segments = []gplan.Segment{
+				{ResourceID: "ahg", StartDate: parseDate("2021-06-07"), EndDate: parseDate("2021-06-08"), Days: 2},
+				{ResourceID: "zz", StartDate: parseDate("2021-06-09"), EndDate: parseDate("2021-06-09"), Days: 1},
+			}
+
+			issues := gplan.Verify(plan)
+			Expect(compareIssues(issues)).Should(ContainElement(gplan.UnknownResourceIssue))
+			Expect(issues[0].Tasks).Should(Equal([]gplan.TaskID{"Tarea1"}))
+			Expect(issues[0].Resources).Should(Equal([]gplan.ResourceID{"zz"}))
+		})
+	})
+
+	When("los totales del plan no coinciden con sus tareas", func() {
+
+		It("Debe devolver los totales", func() {
+			plan.TotalTasks = 2
+			plan.Tasks[0].ResourceID = nil
+
+			Expect(compareIssues(gplan.Verify(plan))).Should(Equal([]gplan.IssueCode{
+				gplan.UnplannedTaskIssue,
+				gplan.PlanDatesIssue,
+				gplan.PlanTotalsIssue,
+			}))
+		})
+	})
+
+})